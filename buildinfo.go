@@ -0,0 +1,83 @@
+package e
+
+import (
+	"errors"
+	"runtime"
+	"runtime/debug"
+)
+
+// BuildInfo identifies the binary which created an Error, so errors crossing
+// service boundaries can be traced back to an exact build.
+type BuildInfo struct {
+	// Path is the main module path, e.g. "github.com/acme/billing".
+	Path string `json:"path"`
+
+	// Version is the main module version, "(devel)" for local builds.
+	Version string `json:"version"`
+
+	// Revision is the VCS revision the binary was built from, if stamped by
+	// the go command.
+	Revision string `json:"revision,omitempty"`
+
+	// GoVersion is the Go toolchain used to build the binary.
+	GoVersion string `json:"go_version"`
+}
+
+// ReadBuildInfo returns the BuildInfo of the running binary, or nil if the
+// binary was not built with module support.
+func ReadBuildInfo() *BuildInfo {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	info := &BuildInfo{
+		Path:      bi.Main.Path,
+		Version:   bi.Main.Version,
+		GoVersion: runtime.Version(),
+	}
+	for _, setting := range bi.Settings {
+		if setting.Key == "vcs.revision" {
+			info.Revision = setting.Value
+		}
+	}
+	return info
+}
+
+// SetBuildInfo stamps every Error which starts a new error stack with info.
+// Stamping is disabled by default and can be disabled again with nil.
+//
+// Usage:
+//
+//	func main() {
+//		e.SetBuildInfo(e.ReadBuildInfo())
+//		...
+//	}
+func SetBuildInfo(info *BuildInfo) {
+	Configure(func(c *Config) {
+		c.BuildInfo = info
+	})
+}
+
+// HasBuildInfo allows custom error types to be used with utility function
+// ErrorBuildInfo().
+type HasBuildInfo interface {
+
+	// BuildInfo returns the build which created the error, if known.
+	BuildInfo() *BuildInfo
+}
+
+func (e errorImpl) BuildInfo() *BuildInfo {
+	return e.buildInfo
+}
+
+// ErrorBuildInfo returns the first unwrapped BuildInfo of an error which
+// implements HasBuildInfo interface. Otherwise returns nil.
+func ErrorBuildInfo(err error) *BuildInfo {
+	for err != nil {
+		if e, ok := err.(HasBuildInfo); ok && e.BuildInfo() != nil {
+			return e.BuildInfo()
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorBuildInfo(t *testing.T) {
+	if got := ErrorBuildInfo(NewError(CodeInternal, "unstamped")); got != nil {
+		t.Errorf("expected no build info by default but got %+v", got)
+	}
+
+	info := &BuildInfo{Path: "example.com/app", Version: "v1.2.3", GoVersion: "go1.14"}
+	SetBuildInfo(info)
+	defer SetBuildInfo(nil)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "NewError", err: NewError(CodeInternal, "stamped")},
+		{name: "Wrap of non-pkg error", err: Wrap(errors.New("basic error"))},
+		{name: "Wrap of Error", err: Wrap(Foo())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorBuildInfo(tt.err); got != info {
+				t.Errorf("\ngot:  %+v\nwant: %+v", got, info)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package e
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// supportBundle is the document produced by SupportBundle.
+type supportBundle struct {
+	Error      string                 `json:"error"`
+	ErrorID    string                 `json:"error_id,omitempty"`
+	Time       *time.Time             `json:"time,omitempty"`
+	Code       string                 `json:"code,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Suggestion string                 `json:"suggestion,omitempty"`
+	HelpURL    string                 `json:"help_url,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Chain      []bundleHop            `json:"chain"`
+	Lineage    []string               `json:"lineage,omitempty"`
+	Stacktrace string                 `json:"stacktrace,omitempty"`
+	BuildInfo  *BuildInfo             `json:"build_info,omitempty"`
+	Recent     []Event                `json:"recent,omitempty"`
+}
+
+// bundleHop describes one error visited by Walk.
+type bundleHop struct {
+	Type    string `json:"type"`
+	Op      string `json:"op,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	Meta    *Meta  `json:"meta,omitempty"`
+	Error   string `json:"error"`
+}
+
+// SupportBundle returns an indented JSON document describing err for attaching
+// to bug reports: the full chain, fields, lineage, stacktrace, build info, host
+// metadata and the errors reported by Recent leading up to it. Build info and
+// the host fields of SetHostInfo are read from the running process if err was
+// not stamped with them. The stacktrace is omitted if StackExportable(err) is
+// false.
+//
+// Usage:
+//
+//	if err := run(); err != nil {
+//		if bundle, bundleErr := e.SupportBundle(err); bundleErr == nil {
+//			ioutil.WriteFile("diagnostics.json", bundle, 0o600)
+//			fmt.Println("Please attach diagnostics.json to your bug report.")
+//		}
+//	}
+func SupportBundle(err error) ([]byte, error) {
+	if err == nil {
+		return nil, errors.New("cannot create support bundle for nil error")
+	}
+
+	bundle := supportBundle{
+		Error:      err.Error(),
+		ErrorID:    ErrorID(err),
+		Code:       string(ErrorCode(err)),
+		Message:    ErrorMessage(err),
+		Suggestion: ErrorSuggestion(err),
+		HelpURL:    ErrorHelpURL(err),
+		Fields:     ErrorFields(err),
+		BuildInfo:  ErrorBuildInfo(err),
+		Recent:     Recent(recentCapacity),
+	}
+	if created := ErrorTime(err); !created.IsZero() {
+		bundle.Time = &created
+	}
+	if bundle.BuildInfo == nil {
+		bundle.BuildInfo = ReadBuildInfo()
+	}
+	if _, stamped := bundle.Fields[KeyPID]; !stamped {
+		if bundle.Fields == nil {
+			bundle.Fields = make(map[string]interface{})
+		}
+		for key, value := range ReadHostInfo().fields() {
+			bundle.Fields[key] = value
+		}
+	}
+	if StackExportable(err) {
+		bundle.Stacktrace = ErrorStacktrace(err)
+	}
+
+	Walk(err, func(err error) bool {
+		hop := bundleHop{
+			Type:  fmt.Sprintf("%T", err),
+			Error: err.Error(),
+		}
+		if e, ok := err.(errorImpl); ok {
+			hop.Op = e.op
+			hop.Code = string(e.code)
+			hop.Message = e.message
+			hop.Meta = e.meta
+		}
+		bundle.Chain = append(bundle.Chain, hop)
+		return true
+	})
+	for _, parent := range Lineage(err) {
+		bundle.Lineage = append(bundle.Lineage, parent.Error())
+	}
+
+	b, marshalErr := json.MarshalIndent(bundle, "", "  ")
+	if marshalErr != nil {
+		return nil, Wrap(marshalErr)
+	}
+	return b, nil
+}
@@ -0,0 +1,50 @@
+package e
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSupportBundle(t *testing.T) {
+	skipNoCapture(t)
+
+	err := Wrap(Derive(Foo(), CodeInternal, "cannot show foo")).SetMessage("oh no")
+
+	b, bundleErr := SupportBundle(err)
+	if bundleErr != nil {
+		t.Fatalf("unexpected error: %v", bundleErr)
+	}
+
+	var bundle supportBundle
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		t.Fatalf("bundle is not valid JSON: %v", err)
+	}
+	if bundle.Error != err.Error() || bundle.Code != CodeInternal || bundle.Message != "oh no" {
+		t.Errorf("unexpected summary: %+v", bundle)
+	}
+	if len(bundle.Chain) != 3 || bundle.Chain[1].Op != "TestSupportBundle" || bundle.Chain[2].Type != "*errors.errorString" {
+		t.Errorf("unexpected chain: %+v", bundle.Chain)
+	}
+	if len(bundle.Lineage) != 1 || bundle.Lineage[0] != "Foo: [database_error] cannot foo" {
+		t.Errorf("unexpected lineage: %q", bundle.Lineage)
+	}
+	if bundle.Stacktrace == "" || bundle.Fields[KeyPID] == nil {
+		t.Errorf("expected stacktrace and host fields")
+	}
+	if !smallFootprint && (len(bundle.Recent) == 0 || bundle.Recent[0].ID != ErrorID(err)) {
+		t.Errorf("expected recent events to start with %q but got %+v", ErrorID(err), bundle.Recent)
+	}
+
+	b, _ = SupportBundle(err.SetNoStackExport())
+	bundle = supportBundle{}
+	json.Unmarshal(b, &bundle)
+	if bundle.Stacktrace != "" {
+		t.Errorf("expected stacktrace to be omitted")
+	}
+
+	if _, err := SupportBundle(nil); err == nil {
+		t.Errorf("expected error for nil")
+	} else if _, ok := err.(Error); ok {
+		t.Errorf("expected a plain error for nil but got %v", err)
+	}
+}
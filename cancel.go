@@ -0,0 +1,80 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// CancellationPolicy controls how hooks see errors caused by context.Canceled,
+// which usually means a client disconnected rather than that something broke.
+type CancellationPolicy int
+
+const (
+	// CancellationDrop does not pass errors caused by cancellation to hooks.
+	// This is the default.
+	CancellationDrop CancellationPolicy = iota
+
+	// CancellationDowngrade passes errors caused by cancellation to hooks with
+	// code CodeCanceled, so they can be recorded at a lower severity. The
+	// returned errors are not changed.
+	CancellationDowngrade
+
+	// CancellationReport passes errors caused by cancellation to hooks like
+	// any other error.
+	CancellationReport
+)
+
+func (p CancellationPolicy) String() string {
+	switch p {
+	case CancellationDrop:
+		return "drop"
+	case CancellationDowngrade:
+		return "downgrade"
+	case CancellationReport:
+		return "report"
+	}
+	return "unknown"
+}
+
+// canceledCount counts new error stacks caused by cancellation, whatever the
+// CancellationPolicy, so they remain visible in DebugVars.
+var canceledCount int64
+
+// SetCancellationPolicy changes how hooks see errors caused by
+// context.Canceled, so that error trackers and metrics are not flooded by
+// benign request cancellations. Such errors are always counted in DebugVars.
+//
+// Usage:
+//
+//	e.SetCancellationPolicy(e.CancellationDowngrade)
+//	e.AddHook(func(err e.Error) {
+//		if e.IsCode(err, e.CodeCanceled) {
+//			canceledRequests.Inc()
+//			return
+//		}
+//		sentry.CaptureException(err)
+//	})
+func SetCancellationPolicy(policy CancellationPolicy) {
+	Configure(func(c *Config) {
+		c.CancellationPolicy = policy
+	})
+}
+
+// applyCancellationPolicy returns the Error hooks should see for e according to
+// the CancellationPolicy of c, and false if they should not see it.
+func (c *Config) applyCancellationPolicy(e errorImpl) (errorImpl, bool) {
+	if !errors.Is(e, context.Canceled) {
+		return e, true
+	}
+	atomic.AddInt64(&canceledCount, 1)
+
+	switch c.CancellationPolicy {
+	case CancellationDrop:
+		return e, false
+	case CancellationDowngrade:
+		e.code = CodeCanceled
+		e = e.withText()
+	}
+	return e, true
+}
@@ -0,0 +1,48 @@
+package e
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCancellationPolicy(t *testing.T) {
+	skipSmallFootprint(t)
+
+	defer SetCancellationPolicy(CancellationDrop)
+
+	tests := []struct {
+		policy    CancellationPolicy
+		wantCodes []Code
+	}{
+		{policy: CancellationDrop, wantCodes: []Code{CodeDatabase}},
+		{policy: CancellationDowngrade, wantCodes: []Code{CodeCanceled, CodeCanceled, CodeDatabase}},
+		{policy: CancellationReport, wantCodes: []Code{"", CodeDatabase, CodeDatabase}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			SetCancellationPolicy(tt.policy)
+
+			var codes []Code
+			remove := AddHook(func(err Error) {
+				codes = append(codes, rawCode(err))
+			})
+			defer remove()
+
+			before := readDebugVars().Canceled
+			returned := Wrap(context.Canceled)
+			NewErrorf(CodeDatabase, "query: %w", context.Canceled)
+			NewError(CodeDatabase, "cannot foo")
+
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("\ngot:  %q\nwant: %q", codes, tt.wantCodes)
+			}
+			if got := readDebugVars().Canceled - before; got != 2 {
+				t.Errorf("expected 2 canceled errors to be counted but got %d", got)
+			}
+			if got := rawCode(returned); got != "" {
+				t.Errorf("expected returned error to be unchanged but got code %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+//go:build !e_noop && !tinygo && !js
+// +build !e_noop,!tinygo,!js
+
+package e
+
+import (
+	"context"
+	"path"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// noCapture is set by building with the e_noop tag, tinygo or GOOS=js. See
+// capture_noop.go.
+const noCapture = false
+
+// helpers holds the names of functions marked with Helper, and helperPCs the
+// call sites of Helper which registered them, so that repeated calls are
+// cheap. helperGen is incremented whenever a function is marked, invalidating
+// the helper flags cached in callSites.
+var (
+	helpers   sync.Map
+	helperPCs sync.Map
+	helperGen int32
+)
+
+// Helper marks the calling function as a helper, like testing.T.Helper. When
+// an op is derived, helper functions are skipped so that thin wrappers around
+// NewError or Wrap report the name of their caller instead of their own.
+// Stacks likewise start at the caller. To skip a fixed number of frames for a
+// single call instead, use With(Skip(n)).
+//
+// Usage:
+//
+//	func wrapDB(err error) error {
+//		e.Helper()
+//		return e.Wrap(err).SetCode("database_error")
+//	}
+func Helper() {
+	var programCounters [1]uintptr
+	if runtime.Callers(2, programCounters[:]) == 0 {
+		return
+	}
+	pc := programCounters[0]
+	if _, ok := helperPCs.Load(pc); ok {
+		return
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if _, loaded := helpers.LoadOrStore(frame.Function, struct{}{}); !loaded {
+		atomic.AddInt32(&helperGen, 1)
+	}
+	helperPCs.Store(pc, struct{}{})
+}
+
+// isHelper reports whether function, a fully qualified function name, was
+// marked with Helper.
+func isHelper(function string) bool {
+	if atomic.LoadInt32(&helperGen) == 0 {
+		return false
+	}
+	_, ok := helpers.Load(function)
+	return ok
+}
+
+// capturePCs returns at most depth program counters of the calling goroutine
+// starting frameOffset levels above capturePCs, like getCallingFunc.
+func capturePCs(frameOffset, depth int) []uintptr {
+	buf := pcPool.Get().(*[]uintptr)
+	if cap(*buf) < depth {
+		*buf = make([]uintptr, depth)
+	}
+	n := runtime.Callers(1+frameOffset, (*buf)[:depth])
+	// Only the frames captured are kept, rather than the whole buffer.
+	programCounters := make([]uintptr, n)
+	copy(programCounters, *buf)
+	pcPool.Put(buf)
+	return programCounters
+}
+
+// pcPool holds the buffers capturePCs captures into.
+var pcPool = sync.Pool{
+	New: func() interface{} { return new([]uintptr) },
+}
+
+// getCallingFunc returns the name of the calling function N levels
+// above getCallingFunc (e.g. 0 for `getCallingFunc` itself)
+func getCallingFunc(frameOffset int) string {
+	op, ok := lookupCallerOp(1 + frameOffset)
+	if !ok {
+		return "unknown"
+	}
+	return op.name
+}
+
+// getCallingFuncLine is like getCallingFunc but appends the file and line of
+// the call, e.g. "Foo@foo.go:12".
+func getCallingFuncLine(frameOffset int) string {
+	op, ok := lookupCallerOp(1 + frameOffset)
+	if !ok {
+		return "unknown"
+	}
+	return op.line
+}
+
+// callerOp holds the ops derived from a call site.
+type callerOp struct {
+	// function is the fully qualified function name, e.g. "main.Foo".
+	function string
+
+	// name is the function name without its package path, e.g. "Foo".
+	name string
+
+	// line is name with the file and line of the call, e.g. "Foo@foo.go:12".
+	line string
+}
+
+func newCallerOp(frame runtime.Frame) *callerOp {
+	// Remove package name (too verbose)
+	name := trimFuncName(frame.Function)
+	return &callerOp{
+		function: frame.Function,
+		name:     name,
+		line:     name + "@" + path.Base(frame.File) + ":" + strconv.Itoa(frame.Line),
+	}
+}
+
+// callSite is what is known about the program counter of a call site.
+type callSite struct {
+	// frames are the ops of the functions the program counter belongs to,
+	// innermost first. There are several if calls were inlined.
+	frames []*callerOp
+
+	// op is the first of frames not marked with Helper, or nil if all are.
+	op *callerOp
+
+	// gen is the helperGen op was chosen with.
+	gen int32
+}
+
+// callSites caches call sites by program counter, so that creating errors
+// repeatedly at the same site does not resolve and trim the function name
+// every time. Values are *callSite.
+var callSites sync.Map
+
+// callSiteOf returns the call site of pc, resolving it on first use.
+func callSiteOf(pc uintptr) *callSite {
+	// Load the generation first, so that a helper marked concurrently
+	// invalidates the site stored below.
+	gen := atomic.LoadInt32(&helperGen)
+	if v, ok := callSites.Load(pc); ok {
+		site := v.(*callSite)
+		if site.gen == gen {
+			return site
+		}
+		site = newCallSite(site.frames, gen)
+		callSites.Store(pc, site)
+		return site
+	}
+
+	var ops []*callerOp
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		ops = append(ops, newCallerOp(frame))
+		if !more {
+			break
+		}
+	}
+	site := newCallSite(ops, gen)
+	callSites.Store(pc, site)
+	return site
+}
+
+func newCallSite(frames []*callerOp, gen int32) *callSite {
+	site := &callSite{frames: frames, gen: gen}
+	for _, op := range frames {
+		if !isHelper(op.function) {
+			site.op = op
+			break
+		}
+	}
+	return site
+}
+
+// lookupCallerOp returns the ops of the calling function N levels above
+// lookupCallerOp, skipping functions marked with Helper. Nothing is allocated
+// once the call sites involved have been resolved.
+func lookupCallerOp(frameOffset int) (*callerOp, bool) {
+	// only need len = 1 to contain the calling function. An array keeps the
+	// buffer on the stack as long as it is not passed to CallersFrames.
+	var programCounters [1]uintptr
+	// base offset is 1 to skip `runtime.Callers` itself
+	if runtime.Callers(1+frameOffset, programCounters[:]) == 0 {
+		return nil, false
+	}
+	if op := callSiteOf(programCounters[0]).op; op != nil {
+		return op, true
+	}
+
+	// The caller is a helper: find the first caller which is not, or the
+	// outermost one.
+	var callers [32]uintptr
+	n := runtime.Callers(1+frameOffset, callers[:])
+	var last *callerOp
+	for _, pc := range callers[:n] {
+		site := callSiteOf(pc)
+		if site.op != nil {
+			return site.op, true
+		}
+		if len(site.frames) > 0 {
+			last = site.frames[len(site.frames)-1]
+		}
+	}
+	return last, last != nil
+}
+
+// panicOrigin returns the name of the function which panicked, i.e. the first
+// frame after runtime.gopanic.
+func panicOrigin() string {
+	programCounters := make([]uintptr, 32)
+	n := runtime.Callers(2, programCounters)
+	frames := runtime.CallersFrames(programCounters[:n])
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" {
+			if next, _ := frames.Next(); next.Function != "" {
+				return trimFuncName(next.Function)
+			}
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	return "unknown"
+}
+
+// framesOf resolves program counters captured by capturePCs.
+func framesOf(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	var stack []Frame
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+		if !more {
+			return stack
+		}
+	}
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the header
+// of its trace, e.g. "goroutine 18 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	var id uint64
+	for _, c := range buf[len("goroutine "):n] {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
+
+// profileLabels returns the pprof labels of ctx, or nil if there are none.
+func profileLabels(ctx context.Context) map[string]string {
+	var labels map[string]string
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = value
+		return true
+	})
+	return labels
+}
@@ -0,0 +1,52 @@
+//go:build e_noop || tinygo || js
+// +build e_noop tinygo js
+
+package e
+
+import "context"
+
+// Building with the e_noop tag degrades constructors to the equivalent of
+// fmt.Errorf with codes and messages: no runtime.Callers calls are made, so
+// ops are never printed and ErrorStacktrace and ErrorStackFrames always return
+// nothing.
+// This allows measuring the overhead of the package, e.g. in canaries.
+//
+// The same fallback is used by tinygo and GOOS=js builds, where stack
+// inspection is unsupported or too costly, so that codes, messages and wrapping
+// can be shared between servers and WASM front ends.
+
+const noCapture = true
+
+func capturePCs(frameOffset, depth int) []uintptr {
+	return nil
+}
+
+func getCallingFunc(frameOffset int) string {
+	return ""
+}
+
+func getCallingFuncLine(frameOffset int) string {
+	return ""
+}
+
+func Helper() {}
+
+func isHelper(function string) bool {
+	return false
+}
+
+func panicOrigin() string {
+	return ""
+}
+
+func framesOf(pcs []uintptr) []Frame {
+	return nil
+}
+
+func goroutineID() uint64 {
+	return 0
+}
+
+func profileLabels(ctx context.Context) map[string]string {
+	return nil
+}
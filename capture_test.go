@@ -0,0 +1,115 @@
+//go:build !e_noop && !tinygo && !js
+// +build !e_noop,!tinygo,!js
+
+package e
+
+import "testing"
+
+func helperOp() string {
+	Helper()
+	return getCallingFunc(1)
+}
+
+func helperNoop() {
+	Helper()
+}
+
+func Test_getCallingFuncAllocsWithHelper(t *testing.T) {
+	if got := helperOp(); got != "Test_getCallingFuncAllocsWithHelper" {
+		t.Fatalf("expected helper to be skipped but got %q", got)
+	}
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{name: "caller is not a helper", fn: func() { getCallingFunc(0) }},
+		{name: "caller is a helper", fn: func() { helperOp() }},
+		{name: "repeated Helper", fn: helperNoop},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn()
+			if allocs := testing.AllocsPerRun(100, tt.fn); allocs != 0 {
+				t.Errorf("allocs = %v, want 0", allocs)
+			}
+		})
+	}
+}
+
+func Test_lookupCallerOpCache(t *testing.T) {
+	var ops []*callerOp
+	for i := 0; i < 2; i++ {
+		op, _ := lookupCallerOp(1)
+		ops = append(ops, op)
+	}
+	other, _ := lookupCallerOp(1)
+
+	if ops[0] != ops[1] {
+		t.Errorf("expected the same call site to hit the cache")
+	}
+	if other == ops[0] || other.line == ops[0].line {
+		t.Errorf("expected another call site to miss the cache but got %+v", other)
+	}
+	if other.name != "Test_lookupCallerOpCache" || other.function != "github.com/kisunji/e.Test_lookupCallerOpCache" {
+		t.Errorf("unexpected op %+v", other)
+	}
+}
+
+func laterHelperOp(markHelper bool) string {
+	if markHelper {
+		Helper()
+	}
+	return getCallingFunc(1)
+}
+
+func Test_lookupCallerOpCacheHelper(t *testing.T) {
+	if isHelper("github.com/kisunji/e.laterHelperOp") {
+		t.Skip("helpers cannot be unmarked, e.g. with -count > 1")
+	}
+	tests := []struct {
+		name       string
+		markHelper bool
+		want       string
+	}{
+		{name: "cached before Helper", want: "laterHelperOp"},
+		{name: "Helper invalidates cache", markHelper: true, want: "Test_lookupCallerOpCacheHelper.func1"},
+		{name: "helpers stay marked", want: "Test_lookupCallerOpCacheHelper.func1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := laterHelperOp(tt.markHelper); got != tt.want {
+				t.Errorf("getCallingFunc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_trimFuncName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "function", in: "github.com/kisunji/e.Wrap", want: "Wrap"},
+		{name: "method", in: "github.com/kisunji/e.(*Config).callingOp", want: "(*Config).callingOp"},
+		{name: "closure", in: "github.com/kisunji/e.TestFoo.func1", want: "TestFoo.func1"},
+		{name: "escaped dot in path", in: "gopkg.in/yaml%2ev3.Unmarshal", want: "Unmarshal"},
+		{name: "no package path", in: "main.main", want: "main"},
+		{name: "no package", in: "main", want: "main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimFuncName(tt.in); got != tt.want {
+				t.Errorf("trimFuncName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getCallingFuncAllocs(t *testing.T) {
+	getCallingFunc(0)
+	if allocs := testing.AllocsPerRun(100, func() { getCallingFunc(0) }); allocs != 0 {
+		t.Errorf("getCallingFunc() allocs = %v, want 0", allocs)
+	}
+}
@@ -0,0 +1,113 @@
+package e
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CatalogFormat is an output format of WriteCatalog.
+type CatalogFormat string
+
+// Formats supported by WriteCatalog.
+const (
+	CatalogMarkdown CatalogFormat = "markdown"
+	CatalogHTML     CatalogFormat = "html"
+)
+
+// Descriptors returns every registered Descriptor, sorted by code.
+func Descriptors() []Descriptor {
+	registry := loadConfig().registry
+	descriptors := make([]Descriptor, 0, len(registry))
+	for _, d := range registry {
+		descriptors = append(descriptors, d)
+	}
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].Code < descriptors[j].Code
+	})
+	return descriptors
+}
+
+// WriteCatalog writes a table of every registered code with its Descriptor to
+// w, for publishing to developer portals. The catalog is generated from the
+// same registry used at runtime; cmd/egen -catalog runs it for a package.
+//
+// Usage:
+//
+//	func TestCatalog(t *testing.T) {
+//		f, _ := os.Create("CODES.md")
+//		defer f.Close()
+//		e.WriteCatalog(f, e.CatalogMarkdown)
+//	}
+func WriteCatalog(w io.Writer, format CatalogFormat) error {
+	header := []string{"Code", "HTTP", "gRPC", "Severity", "Retryable", "Message", "Owner", "Docs"}
+	var rows [][]string
+	for _, d := range Descriptors() {
+		rows = append(rows, []string{
+			string(d.Code),
+			catalogNumber(d.HTTPStatus),
+			catalogNumber(int(d.GRPCCode)),
+			d.Severity.String(),
+			fmt.Sprint(d.Retryable),
+			d.Message,
+			d.Owner,
+			d.HelpURL,
+		})
+	}
+
+	var sb strings.Builder
+	switch format {
+	case CatalogMarkdown:
+		writeMarkdownRow(&sb, header)
+		sb.WriteString(strings.Repeat("| --- ", len(header)) + "|\n")
+		for _, row := range rows {
+			row[0] = "`" + row[0] + "`"
+			if row[7] != "" {
+				row[7] = "[docs](" + row[7] + ")"
+			}
+			writeMarkdownRow(&sb, row)
+		}
+	case CatalogHTML:
+		sb.WriteString("<table>\n<thead>\n")
+		writeHTMLRow(&sb, "th", header)
+		sb.WriteString("</thead>\n<tbody>\n")
+		for _, row := range rows {
+			writeHTMLRow(&sb, "td", row)
+		}
+		sb.WriteString("</tbody>\n</table>\n")
+	default:
+		return fmt.Errorf("unknown catalog format %q", format)
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return Wrap(err)
+	}
+	return nil
+}
+
+// catalogNumber renders unset numbers as blank cells.
+func catalogNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+func writeMarkdownRow(sb *strings.Builder, cells []string) {
+	for _, cell := range cells {
+		sb.WriteString("| ")
+		sb.WriteString(strings.ReplaceAll(cell, "|", "\\|"))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("|\n")
+}
+
+func writeHTMLRow(sb *strings.Builder, tag string, cells []string) {
+	sb.WriteString("<tr>")
+	for _, cell := range cells {
+		sb.WriteString("<" + tag + ">" + html.EscapeString(cell) + "</" + tag + ">")
+	}
+	sb.WriteString("</tr>\n")
+}
@@ -0,0 +1,56 @@
+package e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteCatalog(t *testing.T) {
+	RegisterCode("test_catalog_not_found",
+		WithHTTPStatus(404),
+		WithGRPCCode(5),
+		WithSeverity(SeverityInfo),
+		WithMessage("The <resource> does not exist"),
+		WithOwner("platform"),
+		WithHelpURL("https://example.com/not-found"),
+	)
+
+	tests := []struct {
+		format CatalogFormat
+		want   []string
+	}{
+		{
+			format: CatalogMarkdown,
+			want: []string{
+				"| Code | HTTP | gRPC | Severity | Retryable | Message | Owner | Docs |\n| --- |",
+				"| `test_catalog_not_found` | 404 | 5 | info | false | The <resource> does not exist | platform | [docs](https://example.com/not-found) |\n",
+			},
+		},
+		{
+			format: CatalogHTML,
+			want: []string{
+				"<table>\n<thead>\n<tr><th>Code</th>",
+				"<tr><td>test_catalog_not_found</td><td>404</td><td>5</td><td>info</td><td>false</td><td>The &lt;resource&gt; does not exist</td><td>platform</td><td>https://example.com/not-found</td></tr>\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var sb strings.Builder
+			if err := WriteCatalog(&sb, tt.format); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sb.String(), want) {
+					t.Errorf("expected catalog to contain %q but got\n%s", want, sb.String())
+				}
+			}
+		})
+	}
+
+	if err := WriteCatalog(&strings.Builder{}, "pdf"); err == nil {
+		t.Errorf("expected error for unknown format")
+	} else if _, ok := err.(Error); ok {
+		t.Errorf("expected a plain error for unknown format but got %v", err)
+	}
+}
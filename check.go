@@ -0,0 +1,35 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Check returns nil if cond is true. Otherwise it returns a new Error like
+// NewError, compressing the common "if !ok { return e.NewError(...) }" guard.
+//
+// Usage:
+//
+//	func Foo(bar *Bar) error {
+//		if err := e.Check(bar != nil, "invalid_error", "bar is nil"); err != nil {
+//			return err
+//		}
+//		return doFoo(bar)
+//	}
+func Check(cond bool, code Code, cause string) error {
+	if cond {
+		return nil
+	}
+	return newError(3, code, errors.New(cause))
+}
+
+// Checkf is like Check but formats the cause like NewErrorf. args are only
+// formatted when cond is false.
+func Checkf(cond bool, code Code, fmtCause string, args ...interface{}) error {
+	if cond {
+		return nil
+	}
+	e := newError(3, code, fmt.Errorf(fmtCause, args...))
+	checkFormat(e.op, e.err.Error())
+	return e
+}
@@ -0,0 +1,26 @@
+package e
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	skipNoCapture(t)
+
+	if err := Check(true, CodeInternal, "unreachable"); err != nil {
+		t.Errorf("expected nil but got %v", err)
+	}
+	if err := Checkf(true, CodeInternal, "unreachable: %d", 1); err != nil {
+		t.Errorf("expected nil but got %v", err)
+	}
+
+	err := Check(false, CodeInternal, "bar is nil")
+	if got, want := err.Error(), "TestCheck: [internal_error] bar is nil"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	err = Checkf(false, CodeInternal, "id: %d", 13)
+	if got, want := err.Error(), "TestCheck: [internal_error] id: 13"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if ErrorStacktrace(err) == "" {
+		t.Errorf("expected stacktrace but got none")
+	}
+}
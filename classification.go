@@ -0,0 +1,99 @@
+package e
+
+import (
+	"sort"
+	"sync"
+)
+
+// OpClassification counts the errors observed for one op by Classification.
+type OpClassification struct {
+	Op           string `json:"op"`
+	Total        int    `json:"total"`
+	Unclassified int    `json:"unclassified"`
+}
+
+// ClassificationReport is a snapshot of Classification.
+type ClassificationReport struct {
+	Total        int `json:"total"`
+	Unclassified int `json:"unclassified"`
+
+	// Ops holds the counts of every op, most unclassified errors first.
+	Ops []OpClassification `json:"ops"`
+}
+
+// Classification measures adoption of codes across a codebase by counting the
+// errors which reach a boundary, such as an HTTP or RPC handler, without a
+// code. Errors are counted under their innermost op, i.e. the function which
+// created the error and is the best place to classify it. Errors which were not
+// created by this package are counted under an empty op.
+//
+// Classification is safe for concurrent use.
+type Classification struct {
+	mu  sync.Mutex
+	ops map[string]*OpClassification
+}
+
+// NewClassification returns an empty Classification.
+//
+// Usage:
+//
+//	var classification = e.NewClassification()
+//
+//	func writeError(w http.ResponseWriter, err error) {
+//		classification.Observe(err)
+//		http.Error(w, e.ErrorMessage(err), e.HTTPStatus(err))
+//	}
+//
+//	func reportAdoption() {
+//		report := classification.Report()
+//		log.Printf("%d of %d errors unclassified", report.Unclassified, report.Total)
+//	}
+func NewClassification() *Classification {
+	return &Classification{ops: make(map[string]*OpClassification)}
+}
+
+// Observe counts err as having reached the boundary. nil errors are ignored.
+func (c *Classification) Observe(err error) {
+	if err == nil {
+		return
+	}
+
+	op := ""
+	if ops := ErrorOps(err); len(ops) > 0 {
+		op = ops[len(ops)-1]
+	}
+	unclassified := rawCode(err) == ""
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts, ok := c.ops[op]
+	if !ok {
+		counts = &OpClassification{Op: op}
+		c.ops[op] = counts
+	}
+	counts.Total++
+	if unclassified {
+		counts.Unclassified++
+	}
+}
+
+// Report returns the counts observed so far.
+func (c *Classification) Report() ClassificationReport {
+	c.mu.Lock()
+	report := ClassificationReport{Ops: make([]OpClassification, 0, len(c.ops))}
+	for _, counts := range c.ops {
+		report.Total += counts.Total
+		report.Unclassified += counts.Unclassified
+		report.Ops = append(report.Ops, *counts)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(report.Ops, func(i, j int) bool {
+		a, b := report.Ops[i], report.Ops[j]
+		if a.Unclassified != b.Unclassified {
+			return a.Unclassified > b.Unclassified
+		}
+		return a.Op < b.Op
+	})
+	return report
+}
@@ -0,0 +1,32 @@
+package e
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClassification(t *testing.T) {
+	skipNoCapture(t)
+
+	c := NewClassification()
+	c.Observe(nil)
+	c.Observe(Bar())
+	c.Observe(Buzz())
+	c.Observe(Buzz())
+	c.Observe(Wrap(Buzz()).SetCode(CodeInternal))
+	c.Observe(errors.New("basic error"))
+
+	want := ClassificationReport{
+		Total:        5,
+		Unclassified: 3,
+		Ops: []OpClassification{
+			{Op: "Buzz", Total: 3, Unclassified: 2},
+			{Op: "", Total: 1, Unclassified: 1},
+			{Op: "Foo", Total: 1, Unclassified: 0},
+		},
+	}
+	if got := c.Report(); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package e
+
+import (
+	"errors"
+	"time"
+)
+
+// SetClock replaces the source of the current time used by the package, e.g.
+// by ErrorTime, Memo expiry and WrapDeadline, so time-dependent behavior is deterministic
+// under test. nil restores time.Now.
+func SetClock(now func() time.Time) {
+	Configure(func(c *Config) {
+		c.Clock = now
+	})
+}
+
+// now returns the current time from the configured clock.
+func now() time.Time {
+	return loadConfig().now()
+}
+
+func (c *Config) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock()
+	}
+	return time.Now()
+}
+
+// HasCreationTime allows custom error types to be used with utility function
+// ErrorTime().
+type HasCreationTime interface {
+
+	// CreatedAt returns the time the error was constructed, or the zero time.
+	//
+	// Note: ErrorTime() should be used to retrieve the innermost CreatedAt().
+	CreatedAt() time.Time
+}
+
+func (e errorImpl) CreatedAt() time.Time {
+	return e.created
+}
+
+// ErrorTime returns the innermost non-zero creation time of an error which
+// implements HasCreationTime interface. Otherwise returns the zero time.
+//
+// The time is recorded when a new error stack is created and kept when it is
+// wrapped, so the age of an error can be measured after it has crossed queues
+// and retries.
+//
+// Usage:
+//
+//	if err := job.Run(); err != nil {
+//		staleness.Observe(time.Since(e.ErrorTime(err)).Seconds())
+//	}
+func ErrorTime(err error) time.Time {
+	var created time.Time
+	for err != nil {
+		if e, ok := err.(HasCreationTime); ok && !e.CreatedAt().IsZero() {
+			created = e.CreatedAt()
+		}
+		err = errors.Unwrap(err)
+	}
+	return created
+}
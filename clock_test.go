@@ -0,0 +1,50 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestErrorTime(t *testing.T) {
+	created := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return created })
+	err := Foo()
+	SetClock(func() time.Time { return created.Add(time.Hour) })
+	defer SetClock(nil)
+
+	tests := []struct {
+		name string
+		err  error
+		want time.Time
+	}{
+		{
+			name: "non-pkg error returns zero time",
+			err:  errors.New("basic error"),
+			want: time.Time{},
+		},
+		{
+			name: "returns time of construction",
+			err:  err,
+			want: created,
+		},
+		{
+			name: "wrapping keeps innermost time",
+			err:  Wrap(fmt.Errorf("fmt: %w", Wrap(err))),
+			want: created,
+		},
+		{
+			name: "wrapping non-pkg error records time",
+			err:  Wrap(errors.New("basic error")),
+			want: created.Add(time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorTime(tt.err); !got.Equal(tt.want) {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
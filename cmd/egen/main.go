@@ -0,0 +1,322 @@
+// Command egen generates code for packages using github.com/kisunji/e.
+//
+// It emits an op constant for every exported function and method of a package,
+// named after the op that e derives at runtime, for teams who prefer the
+// `const op = "FuncName"` pattern with compile-checked op strings:
+//
+//	//go:generate egen -dir . -out ops_gen.go
+//
+// generates
+//
+//	const (
+//		opFooBar  = "FooBar"
+//		opRepoGet = "(*Repo).Get"
+//	)
+//
+// Files are selected like go build selects them for any operating system and
+// architecture, honoring the build tags given with -tags, so that the same
+// ops are generated on every platform. A function declared once per platform
+// gets a single constant. egen fails if two ops would share a constant, e.g.
+// a function RepoGet and a method Repo.Get.
+//
+// With -catalog it instead writes a Markdown or HTML catalog of every code
+// registered by a package and its dependencies, with their HTTP and gRPC
+// mappings, messages, owners and doc URLs, for publishing to developer portals:
+//
+//	egen -dir ./codes -catalog CODES.md
+//	egen -dir ./codes -catalog codes.html -format html
+//
+// The catalog is generated from the runtime registry by building and running a
+// small program which imports the package, so codes registered in init
+// functions are included.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("egen: ")
+
+	dir := flag.String("dir", ".", "directory of the package to scan")
+	out := flag.String("out", "ops_gen.go", "file to write, relative to -dir; - for stdout")
+	tags := flag.String("tags", "", "comma-separated build tags to honor when selecting files")
+	catalog := flag.String("catalog", "", "write a catalog of registered codes to this file instead of ops, relative to -dir; - for stdout")
+	catalogFormat := flag.String("format", "markdown", "format of -catalog: markdown or html")
+	flag.Parse()
+
+	if *catalog != "" {
+		out, err := generateCatalog(*dir, *catalogFormat)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *catalog == "-" {
+			os.Stdout.Write(out)
+			return
+		}
+		if err := ioutil.WriteFile(filepath.Join(*dir, *catalog), out, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	src, err := generateOps(*dir, splitTags(*tags))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "-" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(filepath.Join(*dir, *out), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// op is a generated constant.
+type op struct {
+	name  string
+	value string
+	pos   token.Position
+}
+
+// knownOS and knownArch are the values of GOOS and GOARCH files are matched
+// against, following go/build.
+var (
+	knownOS = []string{
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js",
+		"linux", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+	}
+	knownArch = []string{
+		"386", "amd64", "arm", "arm64", "loong64", "mips", "mips64", "mips64le", "mipsle",
+		"ppc64", "ppc64le", "riscv64", "s390x", "wasm",
+	}
+)
+
+// splitTags splits the value of -tags, which like go build accepts tags
+// separated by commas or spaces.
+func splitTags(tags string) []string {
+	return strings.FieldsFunc(tags, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// buildFiles returns the names of the non-test Go files in dir which are
+// built with tags for at least one known GOOS and GOARCH.
+func buildFiles(dir string, tags []string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	ctx := build.Default
+	ctx.BuildTags = tags
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		matched, err := matchAnyPlatform(ctx, dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// matchAnyPlatform reports whether ctx.MatchFile matches name for any known
+// GOOS and GOARCH, with or without cgo.
+func matchAnyPlatform(ctx build.Context, dir, name string) (bool, error) {
+	for _, cgo := range []bool{true, false} {
+		for _, goos := range knownOS {
+			for _, goarch := range knownArch {
+				ctx.CgoEnabled, ctx.GOOS, ctx.GOARCH = cgo, goos, goarch
+				matched, err := ctx.MatchFile(dir, name)
+				if err != nil || matched {
+					return matched, err
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// generateOps returns the source of a file declaring an op constant for every
+// exported function and method of the non-test package in dir, built with
+// tags.
+func generateOps(dir string, tags []string) ([]byte, error) {
+	names, err := buildFiles(dir, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var (
+		pkgName string
+		ops     = make(map[string]op)
+	)
+	for _, name := range names {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		if pkgName != "" && file.Name.Name != pkgName {
+			return nil, fmt.Errorf("expected 1 package in %s but found %s and %s", dir, pkgName, file.Name.Name)
+		}
+		pkgName = file.Name.Name
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			o, ok := opFor(fn)
+			if !ok {
+				continue
+			}
+			o.pos = fset.Position(fn.Pos())
+			if prev, ok := ops[o.name]; ok {
+				// the same function declared in files for different platforms
+				if prev.value == o.value {
+					continue
+				}
+				return nil, fmt.Errorf("%s: %s for %s collides with %s for %s at %s",
+					o.pos, o.name, o.value, prev.name, prev.value, prev.pos)
+			}
+			ops[o.name] = o
+		}
+	}
+	if pkgName == "" {
+		return nil, fmt.Errorf("expected 1 package in %s but found 0", dir)
+	}
+
+	sorted := make([]op, 0, len(ops))
+	for _, o := range ops {
+		sorted = append(sorted, o)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by egen. DO NOT EDIT.\n\npackage %s\n\n", pkgName)
+	if len(sorted) > 0 {
+		buf.WriteString("const (\n")
+		for _, o := range sorted {
+			fmt.Fprintf(&buf, "\t%s = %q\n", o.name, o.value)
+		}
+		buf.WriteString(")\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// opFor returns the op constant for fn if it is an exported function or an
+// exported method of an exported type. The value matches the op derived by
+// package e, e.g. "Foo", "Repo.Get" or "(*Repo).Get".
+func opFor(fn *ast.FuncDecl) (op, bool) {
+	if !fn.Name.IsExported() {
+		return op{}, false
+	}
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return op{name: "op" + fn.Name.Name, value: fn.Name.Name}, true
+	}
+
+	recv := fn.Recv.List[0].Type
+	pointer := false
+	if star, ok := recv.(*ast.StarExpr); ok {
+		pointer = true
+		recv = star.X
+	}
+	// drop type parameters of generic receivers
+	switch index := recv.(type) {
+	case *ast.IndexExpr:
+		recv = index.X
+	case *ast.IndexListExpr:
+		recv = index.X
+	}
+	ident, ok := recv.(*ast.Ident)
+	if !ok || !ident.IsExported() {
+		return op{}, false
+	}
+
+	value := ident.Name + "." + fn.Name.Name
+	if pointer {
+		value = "(*" + ident.Name + ")." + fn.Name.Name
+	}
+	return op{name: "op" + ident.Name + fn.Name.Name, value: value}, true
+}
+
+// catalogProgram prints the catalog of the package imported for its side
+// effects.
+const catalogProgram = `package main
+
+import (
+	"log"
+	"os"
+
+	_ %q
+
+	"github.com/kisunji/e"
+)
+
+func main() {
+	if err := e.WriteCatalog(os.Stdout, e.CatalogFormat(%q)); err != nil {
+		log.Fatal(err)
+	}
+}
+`
+
+// generateCatalog returns the catalog of codes registered by the package in
+// dir. The package is imported by a program written to a temporary directory
+// inside dir, so it is built with the same module as the package.
+func generateCatalog(dir, format string) ([]byte, error) {
+	list := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.Name}}", ".")
+	list.Dir = dir
+	list.Stderr = os.Stderr
+	out, err := list.Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot find package in %s: %w", dir, err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("unexpected go list output %q", out)
+	}
+	if fields[1] == "main" {
+		return nil, fmt.Errorf("cannot import main package %s; move codes to a separate package", fields[0])
+	}
+
+	tmp, err := ioutil.TempDir(dir, "_egen_catalog")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	src := fmt.Sprintf(catalogProgram, fields[0], format)
+	if err := ioutil.WriteFile(filepath.Join(tmp, "main.go"), []byte(src), 0o644); err != nil {
+		return nil, err
+	}
+
+	run := exec.Command("go", "run", ".")
+	run.Dir = tmp
+	run.Stderr = os.Stderr
+	catalog, err := run.Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate catalog: %w", err)
+	}
+	return catalog, nil
+}
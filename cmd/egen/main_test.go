@@ -0,0 +1,194 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateOps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "egen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package repo
+
+type Repo struct{}
+
+type cache struct{}
+
+func New() *Repo { return nil }
+
+func (r *Repo) Get() {}
+
+func (r Repo) List() {}
+
+func (r *Repo) scan() {}
+
+func (c *cache) Get() {}
+
+func helper() {}
+
+type Set[T comparable] struct{}
+
+func (s *Set[T]) Add() {}
+
+type Pair[K comparable, V any] struct{}
+
+func (p Pair[K, V]) Swap() {}
+`
+	test := `package repo
+
+func TestIgnored() {}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "repo.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "repo_test.go"), []byte(test), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generateOps(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `// Code generated by egen. DO NOT EDIT.
+
+package repo
+
+const (
+	opNew      = "New"
+	opPairSwap = "Pair.Swap"
+	opRepoGet  = "(*Repo).Get"
+	opRepoList = "Repo.List"
+	opSetAdd   = "(*Set).Add"
+)
+`
+	if string(got) != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateCatalog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("builds a program with the go command, which is not available")
+	}
+
+	got, err := generateCatalog(filepath.Join("..", "..", "ecodes"), "markdown")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "| `not_found` | 404 | 5 | info | false |"
+	if !strings.Contains(string(got), want) {
+		t.Errorf("expected catalog to contain %q but got\n%s", want, got)
+	}
+
+	if _, err := generateCatalog(".", "markdown"); err == nil {
+		t.Errorf("expected error for main package")
+	}
+}
+
+func TestGenerateOpsBuildConstraints(t *testing.T) {
+	files := map[string]string{
+		"repo.go":         "package repo\n\nfunc New() {}\n",
+		"repo_linux.go":   "package repo\n\nfunc Sync() {}\n",
+		"repo_windows.go": "package repo\n\nfunc Sync() {}\n\nfunc Service() {}\n",
+		"repo_cgo.go":     "//go:build cgo\n\npackage repo\n\nfunc Fast() {}\n",
+		"repo_nocgo.go":   "//go:build !cgo\n\npackage repo\n\nfunc Fast() {}\n",
+		"repo_extra.go":   "//go:build extra\n\npackage repo\n\nfunc Extra() {}\n",
+		"gen.go":          "//go:build ignore\n\npackage main\n\nfunc Generate() {}\n",
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		want string
+	}{
+		{
+			name: "without tags",
+			want: `// Code generated by egen. DO NOT EDIT.
+
+package repo
+
+const (
+	opFast    = "Fast"
+	opNew     = "New"
+	opService = "Service"
+	opSync    = "Sync"
+)
+`,
+		},
+		{
+			name: "with tags",
+			tags: splitTags("extra"),
+			want: `// Code generated by egen. DO NOT EDIT.
+
+package repo
+
+const (
+	opExtra   = "Extra"
+	opFast    = "Fast"
+	opNew     = "New"
+	opService = "Service"
+	opSync    = "Sync"
+)
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writePackage(t, files)
+			defer os.RemoveAll(dir)
+
+			got, err := generateOps(dir, tt.tags)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateOpsCollision(t *testing.T) {
+	dir := writePackage(t, map[string]string{
+		"repo.go": `package repo
+
+type Repo struct{}
+
+func RepoGet() {}
+
+func (r *Repo) Get() {}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := generateOps(dir, nil)
+	if err == nil || !strings.Contains(err.Error(), "opRepoGet for (*Repo).Get collides with opRepoGet for RepoGet") {
+		t.Errorf("expected collision error but got %v", err)
+	}
+}
+
+// writePackage writes files to a new temporary directory and returns it.
+func writePackage(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "egen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
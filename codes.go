@@ -0,0 +1,118 @@
+package e
+
+import "strings"
+
+// Code is a short string describing the type of an error, such as
+// "database_error", to be used by a client or an application. Codes may be
+// hierarchical with dot-separated segments, such as
+// "database.connection.timeout", to categorize errors at several granularities;
+// see CodeHasPrefix and ParentCode. Untyped string constants convert to Code
+// implicitly, but string variables must be converted explicitly, e.g. codes
+// decoded from the wire. Declare codes with RegisterCode so that typos can be
+// caught with SetCodeStrictness.
+type Code string
+
+// Codes assigned by helpers in this package. Applications are free to use their
+// own codes everywhere else.
+const (
+	// CodeValidation is assigned to errors caused by invalid input.
+	CodeValidation Code = "validation_error"
+
+	// CodeTimeout is assigned to errors caused by an exceeded deadline.
+	CodeTimeout Code = "timeout"
+
+	// CodeCanceled is assigned to errors caused by context.Canceled when they
+	// are passed to hooks under CancellationDowngrade.
+	CodeCanceled Code = "canceled"
+
+	// CodePanic is assigned to errors built from a recovered panic.
+	CodePanic Code = "panic"
+
+	// CodeContractViolation is assigned to errors reporting misuse of the
+	// package, such as WrapExpect finding an unexpected code. They are passed
+	// to hooks rather than returned.
+	CodeContractViolation Code = "contract_violation"
+
+	// CodeUnknown is assigned by Ensure to errors which have no code.
+	CodeUnknown Code = "unknown_error"
+)
+
+// isBuiltinCode reports whether code is assigned by this package, so it is
+// always considered registered.
+func isBuiltinCode(code Code) bool {
+	switch code {
+	case CodeValidation, CodeTimeout, CodeCanceled, CodePanic, CodeContractViolation, CodeUnknown:
+		return true
+	}
+	return false
+}
+
+// ParentCode returns the parent of a hierarchical code by removing its last
+// segment, e.g. "database.connection" for "database.connection.timeout".
+// Returns an empty string for codes without a parent.
+func ParentCode(code Code) Code {
+	if i := strings.LastIndexByte(string(code), '.'); i >= 0 {
+		return code[:i]
+	}
+	return ""
+}
+
+// CodeHasPrefix reports whether the outermost code of err is prefix or one of
+// its descendants, matching whole segments: "database" matches
+// "database.connection.timeout" but not "databases.read". Any namespace set
+// with SetCodeNamespace is ignored.
+//
+// Usage:
+//
+//	if e.CodeHasPrefix(err, "database") {
+//		dbErrors.Inc()
+//	}
+func CodeHasPrefix(err error, prefix Code) bool {
+	code := rawCode(err)
+	if prefix == "" || !strings.HasPrefix(string(code), string(prefix)) {
+		return false
+	}
+	return len(code) == len(prefix) || code[len(prefix)] == '.'
+}
+
+// IsCode reports whether the outermost code of err is code. Any namespace set
+// with SetCodeNamespace is ignored.
+//
+// Usage:
+//
+//	if e.IsCode(err, "not_exists") {
+//		return createBar(id)
+//	}
+func IsCode(err error, code Code) bool {
+	return code != "" && rawCode(err) == code
+}
+
+// IsAnyCode reports whether the outermost code of err is one of codes.
+func IsAnyCode(err error, codes ...Code) bool {
+	outermost := rawCode(err)
+	if outermost == "" {
+		return false
+	}
+	for _, code := range codes {
+		if outermost == code {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCode reports whether any error found by Walk, including ones whose code
+// was overridden by an outer SetCode, has code.
+func HasCode(err error, code Code) bool {
+	if code == "" {
+		return false
+	}
+	found := false
+	Walk(err, func(err error) bool {
+		if e, ok := err.(ClientFacing); ok && Code(e.ClientCode()) == code {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
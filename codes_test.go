@@ -0,0 +1,77 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParentCode(t *testing.T) {
+	tests := []struct {
+		code Code
+		want Code
+	}{
+		{code: "database.connection.timeout", want: "database.connection"},
+		{code: "database.connection", want: "database"},
+		{code: "database", want: ""},
+		{code: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			if got := ParentCode(tt.code); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeHasPrefix(t *testing.T) {
+	err := Wrap(NewError("database.connection.timeout", "dial timeout"))
+	tests := []struct {
+		name   string
+		err    error
+		prefix Code
+		want   bool
+	}{
+		{name: "matches root segment", err: err, prefix: "database", want: true},
+		{name: "matches intermediate segment", err: err, prefix: "database.connection", want: true},
+		{name: "matches whole code", err: err, prefix: "database.connection.timeout", want: true},
+		{name: "does not match partial segment", err: err, prefix: "database.conn", want: false},
+		{name: "does not match other code", err: NewError("databases.read", "cannot read"), prefix: "database", want: false},
+		{name: "empty prefix does not match", err: err, prefix: "", want: false},
+		{name: "non-pkg error does not match", err: errors.New("basic error"), prefix: "database", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeHasPrefix(tt.err, tt.prefix); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodePredicates(t *testing.T) {
+	err := Wrap(Wrap(Foo()).SetCode(CodeInternal))
+	joined := joinError{errors.New("basic error"), NewError(CodeUnexpected, "second")}
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{name: "IsCode matches outermost code", got: IsCode(err, CodeInternal), want: true},
+		{name: "IsCode ignores inner codes", got: IsCode(err, CodeDatabase), want: false},
+		{name: "IsCode never matches empty code", got: IsCode(errors.New("basic error"), ""), want: false},
+		{name: "IsAnyCode matches one of codes", got: IsAnyCode(err, CodeDatabase, CodeInternal), want: true},
+		{name: "IsAnyCode ignores inner codes", got: IsAnyCode(err, CodeDatabase, CodeUnexpected), want: false},
+		{name: "HasCode finds overridden code", got: HasCode(err, CodeDatabase), want: true},
+		{name: "HasCode finds codes of joined errors", got: HasCode(joined, CodeUnexpected), want: true},
+		{name: "HasCode reports missing code", got: HasCode(err, CodeUnexpected), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
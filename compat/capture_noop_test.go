@@ -0,0 +1,8 @@
+//go:build e_noop || tinygo || js
+// +build e_noop tinygo js
+
+package compat
+
+func init() {
+	noCapture = true
+}
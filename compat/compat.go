@@ -0,0 +1,104 @@
+// Package compat exposes the API of github.com/pkg/errors implemented on top of
+// package e, so large codebases can switch import paths mechanically and adopt
+// codes and messages incrementally.
+//
+// Errors are rendered in the format of package e, e.g. Wrap(err, "read config")
+// renders "Foo: (read config): cause" rather than "read config: cause".
+package compat
+
+import (
+	"errors"
+
+	"github.com/kisunji/e"
+)
+
+// New returns an error with the supplied message and a stacktrace.
+func New(message string) error {
+	e.Helper()
+	return e.NewError("", message)
+}
+
+// Errorf formats according to a format specifier and returns an error with a
+// stacktrace.
+func Errorf(format string, args ...interface{}) error {
+	e.Helper()
+	return e.NewErrorf("", format, args...)
+}
+
+// Wrap returns an error annotating err with message and the calling function.
+// If err is nil, Wrap returns nil.
+func Wrap(err error, message string) error {
+	e.Helper()
+	if err == nil {
+		return nil
+	}
+	return e.Wrap(err, message)
+}
+
+// Wrapf returns an error annotating err with a formatted message and the
+// calling function. If err is nil, Wrapf returns nil.
+func Wrapf(err error, format string, args ...interface{}) error {
+	e.Helper()
+	if err == nil {
+		return nil
+	}
+	return e.Wrapf(err, format, args...)
+}
+
+// WithStack annotates err with the calling function and a stacktrace, if it
+// does not have one already. If err is nil, WithStack returns nil.
+func WithStack(err error) error {
+	e.Helper()
+	if err == nil {
+		return nil
+	}
+	return e.Wrap(err)
+}
+
+// WithMessage annotates err with message. If err is nil, WithMessage returns nil.
+func WithMessage(err error, message string) error {
+	e.Helper()
+	if err == nil {
+		return nil
+	}
+	return e.Wrap(err, message)
+}
+
+// WithMessagef annotates err with a formatted message. If err is nil,
+// WithMessagef returns nil.
+func WithMessagef(err error, format string, args ...interface{}) error {
+	e.Helper()
+	if err == nil {
+		return nil
+	}
+	return e.Wrapf(err, format, args...)
+}
+
+// Cause returns the innermost error of err by unwrapping it until it no longer
+// wraps another error.
+func Cause(err error) error {
+	for err != nil {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return nil
+}
+
+// Is reports whether any error in err's chain matches target.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target, and if so, sets
+// target to that error value and returns true.
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err, if any.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
@@ -0,0 +1,70 @@
+package compat
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+var errBase = errors.New("base error")
+
+// noCapture is set when ops and stacks are not captured, by builds with
+// e_noop, tinygo or GOOS=js. See capture_noop_test.go.
+var noCapture bool
+
+// skipNoCapture skips a test of ops or stacks, which this build does not
+// capture.
+func skipNoCapture(t *testing.T) {
+	t.Helper()
+	if noCapture {
+		t.Skip("ops and stacks are not captured by this build")
+	}
+}
+
+func TestCompat(t *testing.T) {
+	skipNoCapture(t)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "New", err: New("cannot foo"), want: "TestCompat: cannot foo"},
+		{name: "Errorf", err: Errorf("id: %d", 13), want: "TestCompat: id: 13"},
+		{name: "Wrap", err: Wrap(errBase, "read config"), want: "TestCompat: (read config): base error"},
+		{name: "Wrapf", err: Wrapf(errBase, "id: %d", 13), want: "TestCompat: (id: 13): base error"},
+		{name: "WithStack", err: WithStack(errBase), want: "TestCompat: base error"},
+		{name: "WithMessage", err: WithMessage(errBase, "oh no"), want: "TestCompat: (oh no): base error"},
+		{name: "WithMessagef", err: WithMessagef(errBase, "id: %d", 13), want: "TestCompat: (id: 13): base error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatNil(t *testing.T) {
+	if Wrap(nil, "x") != nil || Wrapf(nil, "x") != nil || WithStack(nil) != nil ||
+		WithMessage(nil, "x") != nil || WithMessagef(nil, "x") != nil || Cause(nil) != nil {
+		t.Errorf("expected nil errors to stay nil")
+	}
+}
+
+func TestCause(t *testing.T) {
+	skipNoCapture(t)
+
+	err := Wrap(WithStack(errBase), "read config")
+	if got := Cause(err); got != errBase {
+		t.Errorf("\ngot:  %v\nwant: %v", got, errBase)
+	}
+	if !Is(err, errBase) {
+		t.Errorf("expected Is to find base error")
+	}
+	if got := e.ErrorStacktrace(err); got == "" {
+		t.Errorf("expected stacktrace")
+	}
+}
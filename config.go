@@ -0,0 +1,228 @@
+package e
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config holds every package-level setting. The active Config is an immutable
+// snapshot which is swapped atomically by Configure, so reconfiguring at runtime
+// (e.g. enabling stack capture during an incident) is race-free and reading it
+// on the hot path is cheap.
+type Config struct {
+	// CodeNamespace prefixes codes returned by ErrorCode. See SetCodeNamespace.
+	CodeNamespace string
+
+	// ErrorTemplate is the layout of Error(). Empty means DefaultErrorTemplate.
+	// See SetErrorTemplate.
+	ErrorTemplate string
+
+	// QuietCodes skip stack capture, hooks and metrics. See RegisterQuietCodes.
+	QuietCodes map[Code]bool
+
+	// BuildInfo and HostInfo are stamped on new error stacks when non-nil.
+	// See SetBuildInfo and SetHostInfo.
+	BuildInfo *BuildInfo
+	HostInfo  *HostInfo
+
+	// Clock is the source of the current time. nil means time.Now.
+	// See SetClock.
+	Clock func() time.Time
+
+	// DevMode makes contract violations panic. See SetDevMode.
+	DevMode bool
+
+	// IDGenerator returns the correlation ID of new error stacks. nil means
+	// random IDs. See SetIDGenerator.
+	IDGenerator func() string
+
+	// CancellationPolicy controls how hooks see errors caused by
+	// context.Canceled. See SetCancellationPolicy.
+	CancellationPolicy CancellationPolicy
+
+	// MaxStackDepth caps the number of frames captured for new error stacks.
+	// 0 means 64. See SetMaxStackDepth.
+	MaxStackDepth int
+
+	// Symbolizer resolves stacks into frames. nil means RuntimeSymbolizer.
+	// See SetSymbolizer.
+	Symbolizer Symbolizer
+
+	// StackFormatter renders stacks for ErrorStacktrace. nil means
+	// GoroutineStackFormatter. See SetStackFormatter.
+	StackFormatter StackFormatter
+
+	// StackTrim cleans stacks when they are symbolized. nil disables
+	// cleaning. See SetStackTrim.
+	StackTrim *StackTrim
+
+	// OpLines appends the file and line of the call to ops. See SetOpLines.
+	OpLines bool
+
+	// StackSampling captures a stack for 1 in StackSampling new error stacks
+	// of each code. 0 captures every stack. See SetStackSampling.
+	StackSampling int
+
+	// GoroutineInfo records the goroutine ID and pprof labels of new errors.
+	// See SetGoroutineInfo.
+	GoroutineInfo bool
+
+	// CodeStrictness controls validation of codes against the registry.
+	// See SetCodeStrictness.
+	CodeStrictness Strictness
+
+	// MaxErrorSize is the SizeOf above which attaching data to an Error is
+	// reported as a contract violation. 0 disables the check.
+	// See SetMaxErrorSize.
+	MaxErrorSize int
+
+	// errorTmpl is ErrorTemplate parsed by Configure; nil for the default.
+	errorTmpl errorTemplate
+
+	// hooks, jobHooks and registry are managed by AddHook, AddJobHook and
+	// Register. They are replaced rather than modified so snapshots can share
+	// them.
+	hooks    []*hook
+	jobHooks []*jobHook
+	registry map[Code]Descriptor
+
+	// scoped is set for Configs created by WithConfig.
+	scoped bool
+
+	// noStack is set for per-call Configs of a Builder with WithoutStack.
+	noStack bool
+}
+
+var (
+	// configMu serializes updates; reads only load config.
+	configMu sync.Mutex
+	config   atomic.Value // *Config
+)
+
+// Configure atomically updates the package configuration. fn receives a copy of
+// the current Config which is published once fn returns, so concurrent readers
+// see either the old or the new settings, never a mix. An error is returned,
+// and nothing is changed, if the resulting Config is invalid.
+//
+// Usage:
+//
+//	err := e.Configure(func(c *e.Config) {
+//		c.CodeNamespace = "billing"
+//		c.QuietCodes["not_exists"] = true
+//	})
+func Configure(fn func(*Config)) error {
+	return updateConfig(func(c *Config) error {
+		fn(c)
+		return nil
+	})
+}
+
+// CurrentConfig returns a copy of the active Config.
+func CurrentConfig() Config {
+	return *loadConfig().clone()
+}
+
+// zeroConfig is the active Config until one is published. It is shared so
+// that texts cached by Error() remain valid.
+var zeroConfig = &Config{}
+
+// loadConfig returns the active Config. It may be called before init, e.g. by
+// package-level sentinel errors, hence the zero Config fallback.
+func loadConfig() *Config {
+	if c, ok := config.Load().(*Config); ok {
+		return c
+	}
+	return zeroConfig
+}
+
+// updateConfig publishes a copy of the active Config modified by fn, unless fn
+// returns an error or the result is invalid.
+func updateConfig(fn func(*Config) error) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	c := loadConfig().clone()
+	if err := fn(c); err != nil {
+		return err
+	}
+
+	if err := c.prepare(); err != nil {
+		return err
+	}
+
+	config.Store(c)
+	return nil
+}
+
+// prepare validates c and derives its unexported state.
+func (c *Config) prepare() error {
+	c.errorTmpl = nil
+	if c.ErrorTemplate != "" && c.ErrorTemplate != DefaultErrorTemplate {
+		parsed, err := parseErrorTemplate(c.ErrorTemplate)
+		if err != nil {
+			return err
+		}
+		c.errorTmpl = parsed
+	}
+	return nil
+}
+
+// clone returns a copy of c which can be modified without affecting c.
+func (c *Config) clone() *Config {
+	clone := *c
+	clone.scoped = false
+	clone.QuietCodes = make(map[Code]bool, len(c.QuietCodes))
+	for code, quiet := range c.QuietCodes {
+		clone.QuietCodes[code] = quiet
+	}
+	return &clone
+}
+
+// SetCodeNamespace makes ErrorCode prefix every code with namespace, e.g.
+// "billing.database_error", so aggregated multi-service dashboards can attribute
+// codes to their owning service. Codes stored on errors are left untouched.
+// An empty namespace removes the prefix.
+func SetCodeNamespace(namespace string) {
+	Configure(func(c *Config) {
+		c.CodeNamespace = namespace
+	})
+}
+
+func getCodeNamespace() string {
+	return loadConfig().CodeNamespace
+}
+
+// RegisterQuietCodes marks codes which are part of normal control flow, such as
+// "not_exists" or "validation_error". NewError and NewErrorf skip the costly
+// stack capture for quiet codes, so ErrorStacktrace returns "" for them, as
+// well as hooks, the counters of DebugVars and Recent.
+func RegisterQuietCodes(codes ...Code) {
+	Configure(func(c *Config) {
+		for _, code := range codes {
+			c.QuietCodes[code] = true
+		}
+	})
+}
+
+// UnregisterQuietCodes reverts RegisterQuietCodes for codes.
+func UnregisterQuietCodes(codes ...Code) {
+	Configure(func(c *Config) {
+		for _, code := range codes {
+			delete(c.QuietCodes, code)
+		}
+	})
+}
+
+func isQuietCode(code Code) bool {
+	return loadConfig().QuietCodes[code]
+}
+
+// SetDevMode makes contract violations, such as WrapExpect finding an
+// unexpected code, panic instead of being reported to hooks. It is intended
+// for development and test builds.
+func SetDevMode(enabled bool) {
+	Configure(func(c *Config) {
+		c.DevMode = enabled
+	})
+}
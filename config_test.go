@@ -0,0 +1,92 @@
+package e
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetCodeNamespace(t *testing.T) {
+	defer SetCodeNamespace("")
+
+	err := Wrap(NewError(CodeDatabase, "cannot foo"))
+	SetCodeNamespace("billing")
+
+	if got, want := ErrorCode(err), Code("billing."+CodeDatabase); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorCode(NewError("", "no code")); got != "" {
+		t.Errorf("expected empty code to stay empty but got %q", got)
+	}
+	if got := rawCode(err); got != CodeDatabase {
+		t.Errorf("expected raw code to be preserved but got %q", got)
+	}
+}
+
+func TestRegisterQuietCodes(t *testing.T) {
+	skipNoCapture(t)
+
+	RegisterQuietCodes("not_exists", "validation_error")
+	defer UnregisterQuietCodes("not_exists", "validation_error")
+
+	if got := ErrorStacktrace(NewError("not_exists", "no bar")); got != "" {
+		t.Errorf("expected no stacktrace for quiet code but got %q", got)
+	}
+	if got := ErrorStacktrace(NewErrorf("validation_error", "bad id: %d", 1)); got != "" {
+		t.Errorf("expected no stacktrace for quiet code but got %q", got)
+	}
+	if got := ErrorStacktrace(Wrap(NewError("not_exists", "no bar"))); got != "" {
+		t.Errorf("expected no stacktrace when wrapping quiet code but got %q", got)
+	}
+	if got := ErrorStacktrace(NewError(CodeDatabase, "cannot foo")); got == "" {
+		t.Errorf("expected stacktrace for other codes but got none")
+	}
+
+	UnregisterQuietCodes("not_exists")
+	if got := ErrorStacktrace(NewError("not_exists", "no bar")); got == "" {
+		t.Errorf("expected stacktrace for unregistered code but got none")
+	}
+}
+
+func TestQuietCodesAreNotRecorded(t *testing.T) {
+	RegisterQuietCodes("not_exists")
+	defer UnregisterQuietCodes("not_exists")
+
+	recorded := atomic.LoadUint64(&recentNext)
+	Wrap(NewError("not_exists", "no bar"))
+	if atomic.LoadUint64(&recentNext) != recorded {
+		t.Errorf("expected quiet code not to be recorded for Recent")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	defer Configure(func(c *Config) {
+		c.CodeNamespace = ""
+		delete(c.QuietCodes, "not_exists")
+	})
+
+	before := CurrentConfig()
+	err := Configure(func(c *Config) {
+		c.CodeNamespace = "billing"
+		c.QuietCodes["not_exists"] = true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if before.QuietCodes["not_exists"] {
+		t.Errorf("expected earlier snapshot not to be modified")
+	}
+	if got := CurrentConfig(); got.CodeNamespace != "billing" || !got.QuietCodes["not_exists"] {
+		t.Errorf("expected config to be updated but got %+v", got)
+	}
+
+	err = Configure(func(c *Config) {
+		c.CodeNamespace = "ignored"
+		c.ErrorTemplate = "{op}"
+	})
+	if err == nil {
+		t.Errorf("expected invalid template to be rejected")
+	}
+	if got := CurrentConfig().CodeNamespace; got != "billing" {
+		t.Errorf("expected invalid config not to be applied but got namespace %q", got)
+	}
+}
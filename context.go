@@ -0,0 +1,70 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// configKey is the context key of a scoped Config.
+type configKey struct{}
+
+// WithConfig returns a copy of ctx carrying a Config for errors created with it
+// by NewErrorContext, WrapContext and WrapDeadline. override receives a copy of
+// the active Config, so a request can e.g. force stack capture of quiet codes
+// or a more verbose template while the global policy stays lean. The scoped
+// Config is a snapshot: later changes to the active Config do not affect it.
+// An error is returned, and ctx is returned unchanged, if the resulting Config
+// is invalid.
+//
+// Errors keep rendering with the scoped Config after they leave the request.
+// Settings which are read when errors are inspected rather than created, such
+// as CodeNamespace, are not scoped.
+//
+// Usage:
+//
+//	if r.Header.Get("X-Debug-Errors") != "" {
+//		ctx, _ = e.WithConfig(ctx, func(c *e.Config) {
+//			c.QuietCodes = nil
+//		})
+//	}
+func WithConfig(ctx context.Context, override func(*Config)) (context.Context, error) {
+	c := configFrom(ctx).clone()
+	override(c)
+	if err := c.prepare(); err != nil {
+		return ctx, err
+	}
+	c.scoped = true
+	return context.WithValue(ctx, configKey{}, c), nil
+}
+
+// configFrom returns the Config scoped to ctx by WithConfig, or the active Config.
+func configFrom(ctx context.Context) *Config {
+	if c, ok := ctx.Value(configKey{}).(*Config); ok {
+		return c
+	}
+	return loadConfig()
+}
+
+// NewErrorContext is NewError using the Config scoped to ctx by WithConfig, if
+// any. The pprof labels of ctx are recorded if SetGoroutineInfo is enabled.
+func NewErrorContext(ctx context.Context, code Code, cause string) Error {
+	c := configFrom(ctx)
+	return newErrorIn(c, 3, code, errors.New(cause)).withProfileLabels(ctx, c)
+}
+
+// WrapContext is Wrap using the Config scoped to ctx by WithConfig, if any.
+// The pprof labels of ctx are recorded if SetGoroutineInfo is enabled.
+func WrapContext(ctx context.Context, err error, optionalInfo ...string) Error {
+	if err == nil {
+		return nil
+	}
+
+	innerErr := err
+	if len(optionalInfo) > 0 {
+		innerErr = fmt.Errorf("(%v): %w", optionalInfo[0], err) // localizer.Ignore
+	}
+
+	c := configFrom(ctx)
+	return wrapIn(c, innerErr, 3).withProfileLabels(ctx, c)
+}
@@ -0,0 +1,70 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWithConfig(t *testing.T) {
+	skipNoCapture(t)
+
+	RegisterQuietCodes("not_exists")
+	defer UnregisterQuietCodes("not_exists")
+
+	ctx, err := WithConfig(context.Background(), func(c *Config) {
+		c.QuietCodes = nil
+		c.ErrorTemplate = "[{code}] {op}: {cause}"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("scoped config captures stack of quiet codes", func(t *testing.T) {
+		if ErrorStacktrace(NewErrorContext(ctx, "not_exists", "no bar")) == "" {
+			t.Errorf("expected stacktrace but got none")
+		}
+		if ErrorStacktrace(WrapContext(ctx, NewError("not_exists", "no bar").SetNoStackExport())) == "" {
+			t.Errorf("expected stacktrace when wrapping quiet code but got none")
+		}
+	})
+	t.Run("active config is unaffected", func(t *testing.T) {
+		if got := ErrorStacktrace(NewErrorContext(context.Background(), "not_exists", "no bar")); got != "" {
+			t.Errorf("expected no stacktrace but got %q", got)
+		}
+		if got, want := Foo().Error(), "Foo: [database_error] cannot foo"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("scoped template is kept when wrapped", func(t *testing.T) {
+		err := Wrap(WrapContext(ctx, errors.New("basic error")).SetCode(CodeDatabase))
+		want := "TestWithConfig.func4: [database_error] TestWithConfig.func4: basic error"
+		if got := err.Error(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("invalid config is rejected", func(t *testing.T) {
+		got, err := WithConfig(ctx, func(c *Config) {
+			c.ErrorTemplate = "{op}"
+		})
+		if err == nil || got != ctx {
+			t.Errorf("expected error and unchanged context but got %v", err)
+		}
+	})
+}
+
+func TestWrapContext(t *testing.T) {
+	skipNoCapture(t)
+
+	if WrapContext(context.Background(), nil) != nil {
+		t.Errorf("expected nil")
+	}
+	err := WrapContext(context.Background(), Foo(), "failed to fizz")
+	if got, want := err.Error(), "TestWrapContext: (failed to fizz): Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := fmt.Sprint(NewErrorContext(context.Background(), CodeInternal, "oops")), "TestWrapContext: [internal_error] oops"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
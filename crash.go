@@ -0,0 +1,62 @@
+package e
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// CrashHandler reports the last error before a crash. It must be deferred
+// directly, typically at the top of main or of a goroutine. It recovers a
+// panic, builds an Error with code CodePanic carrying the full stacktrace and a
+// snapshot of runtime info (goroutine count and memory stats), then re-panics
+// with that Error so the process still crashes.
+//
+// Usage:
+//
+//	func main() {
+//		defer e.CrashHandler()
+//		...
+//	}
+func CrashHandler() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	panic(newPanicError(r))
+}
+
+// newPanicError builds an Error from a recovered value. Must be called from the
+// deferred function which recovered so the stacktrace includes the panic site.
+func newPanicError(r interface{}) Error {
+	cause, ok := r.(error)
+	if !ok {
+		cause = fmt.Errorf("%v", r)
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	c := loadConfig()
+	e := errorImpl{
+		op:   panicOrigin(),
+		code: CodePanic,
+		err: fmt.Errorf("(goroutines: %d, heap alloc: %d bytes, sys: %d bytes): %w", // localizer.Ignore
+			runtime.NumGoroutine(), mem.HeapAlloc, mem.Sys, cause),
+	}.withStack(c, 1).withOrigin(c)
+	recordOrigin(e)
+	c.notifyHooks(e)
+	return e
+}
+
+// trimFuncName removes the package path from a fully qualified function name,
+// e.g. "github.com/kisunji/e.Foo.func1" becomes "Foo.func1".
+func trimFuncName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
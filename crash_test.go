@@ -0,0 +1,49 @@
+package e
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCrashHandler(t *testing.T) {
+	skipNoCapture(t)
+
+	t.Run("re-panics with an Error", func(t *testing.T) {
+		defer func() {
+			err, ok := recover().(Error)
+			if !ok {
+				t.Fatalf("expected panic with Error")
+			}
+			if got := ErrorCode(err); got != CodePanic {
+				t.Errorf("\ngot:  %q\nwant: %q", got, CodePanic)
+			}
+			if got := err.Error(); !strings.HasPrefix(got, "TestCrashHandler.func1.2: [panic] (goroutines: ") ||
+				!strings.HasSuffix(got, "): oh no") {
+				t.Errorf("unexpected error text %q", got)
+			}
+			if !strings.Contains(ErrorStacktrace(err), "e.panicky") {
+				t.Errorf("expected stacktrace to include panic site")
+			}
+		}()
+		panicky(func() { panic("oh no") })
+	})
+	t.Run("keeps panicked errors", func(t *testing.T) {
+		errPanic := errors.New("oh no")
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, errPanic) {
+				t.Errorf("expected panic Error to wrap %v", errPanic)
+			}
+		}()
+		panicky(func() { panic(errPanic) })
+	})
+	t.Run("does nothing without panic", func(t *testing.T) {
+		panicky(func() {})
+	})
+}
+
+func panicky(fn func()) {
+	defer CrashHandler()
+	fn()
+}
@@ -0,0 +1,57 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WrapDeadline wraps err like Wrap and, when ctx has a deadline, records the
+// time remaining before it or by how much it was overrun, both in the error
+// text and in the KeyDeadlineRemaining or KeyDeadlineOverrun field. If the
+// deadline was exceeded the code is set to CodeTimeout, giving consistent
+// timeout diagnostics across handlers. The Config scoped to ctx by WithConfig,
+// if any, is used like WrapContext.
+//
+// Usage:
+//
+//	err := db.GetBar(ctx, id)
+//	if err != nil {
+//		return e.WrapDeadline(ctx, err)
+//		// "Foo: [timeout] (deadline exceeded by 1.2s): GetBar: cannot get bar"
+//	}
+func WrapDeadline(ctx context.Context, err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	c := configFrom(ctx)
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return wrapIn(c, err, 3)
+	}
+
+	remaining := deadline.Sub(c.now())
+	exceeded := remaining <= 0 ||
+		errors.Is(ctx.Err(), context.DeadlineExceeded) ||
+		errors.Is(err, context.DeadlineExceeded)
+
+	if exceeded {
+		overrun := roundDuration(-remaining)
+		wrapped := wrapIn(c, fmt.Errorf("(deadline exceeded by %v): %w", overrun, err), 3) // localizer.Ignore
+		wrapped.code = CodeTimeout
+		return wrapped.withText().SetField(KeyDeadlineOverrun, overrun)
+	}
+	remaining = roundDuration(remaining)
+	wrapped := wrapIn(c, fmt.Errorf("(deadline in %v): %w", remaining, err), 3) // localizer.Ignore
+	return wrapped.SetField(KeyDeadlineRemaining, remaining)
+}
+
+// roundDuration keeps durations readable in error strings.
+func roundDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d.Round(time.Millisecond)
+}
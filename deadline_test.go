@@ -0,0 +1,53 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrapDeadline(t *testing.T) {
+	skipNoCapture(t)
+
+	t.Run("no deadline only wraps", func(t *testing.T) {
+		err := WrapDeadline(context.Background(), errors.New("basic error"))
+		if got, want := err.Error(), "TestWrapDeadline.func1: basic error"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("records remaining time", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		err := WrapDeadline(ctx, Foo())
+		if !strings.Contains(err.Error(), "(deadline in ") {
+			t.Errorf("expected remaining time but got %q", err)
+		}
+		if _, ok := ErrorFields(err)[KeyDeadlineRemaining]; !ok {
+			t.Errorf("expected remaining field")
+		}
+		if got := ErrorCode(err); got != CodeDatabase {
+			t.Errorf("expected code to be kept but got %q", got)
+		}
+	})
+	t.Run("exceeded deadline sets timeout code", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		err := WrapDeadline(ctx, ctx.Err())
+		if !strings.Contains(err.Error(), "(deadline exceeded by ") {
+			t.Errorf("expected overrun time but got %q", err)
+		}
+		if _, ok := ErrorFields(err)[KeyDeadlineOverrun]; !ok {
+			t.Errorf("expected overrun field")
+		}
+		if got := ErrorCode(err); got != CodeTimeout {
+			t.Errorf("\ngot:  %q\nwant: %q", got, CodeTimeout)
+		}
+	})
+	t.Run("nil stays nil", func(t *testing.T) {
+		if WrapDeadline(context.Background(), nil) != nil {
+			t.Errorf("expected nil")
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package e
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// createdByCode counts new error stacks by the code they were created with.
+// Values are *int64.
+var createdByCode sync.Map
+
+// countCreated increments the counter of code. Errors without a code, such as
+// wrapped non-pkg errors, are counted under "".
+func countCreated(code Code) {
+	n, ok := createdByCode.Load(code)
+	if !ok {
+		n, _ = createdByCode.LoadOrStore(code, new(int64))
+	}
+	atomic.AddInt64(n.(*int64), 1)
+}
+
+// debugConfig is the part of Config reported by DebugVars.
+type debugConfig struct {
+	CodeNamespace      string   `json:"code_namespace"`
+	ErrorTemplate      string   `json:"error_template"`
+	QuietCodes         []string `json:"quiet_codes"`
+	DevMode            bool     `json:"dev_mode"`
+	BuildInfo          bool     `json:"build_info"`
+	HostInfo           bool     `json:"host_info"`
+	CustomClock        bool     `json:"custom_clock"`
+	CustomIDs          bool     `json:"custom_ids"`
+	CustomSymbolizer   bool     `json:"custom_symbolizer"`
+	MaxErrorSize       int      `json:"max_error_size"`
+	Hooks              int      `json:"hooks"`
+	RegisteredCodes    int      `json:"registered_codes"`
+	CancellationPolicy string   `json:"cancellation_policy"`
+	SmallFootprint     bool     `json:"small_footprint"`
+	StackSampling      int      `json:"stack_sampling"`
+	MaxStackDepth      int      `json:"max_stack_depth"`
+	CodeStrictness     string   `json:"code_strictness"`
+	OpLines            bool     `json:"op_lines"`
+	GoroutineInfo      bool     `json:"goroutine_info"`
+}
+
+// debugVars is the document reported by DebugVars.
+type debugVars struct {
+	Config        debugConfig      `json:"config"`
+	CreatedByCode map[string]int64 `json:"created_by_code"`
+
+	// Canceled counts new error stacks caused by context.Canceled, which may
+	// be hidden from hooks by the CancellationPolicy.
+	Canceled int64 `json:"canceled"`
+
+	// StackSamples counts the new error stacks of each code seen by the
+	// sampler of SetStackSampling, which captures a stack for the first and
+	// then every StackSampling-th of them.
+	StackSamples map[string]uint64 `json:"stack_samples"`
+}
+
+// readDebugVars returns the document reported by DebugVars.
+func readDebugVars() debugVars {
+	c := loadConfig()
+	vars := debugVars{
+		Config: debugConfig{
+			CodeNamespace:      c.CodeNamespace,
+			ErrorTemplate:      c.ErrorTemplate,
+			QuietCodes:         make([]string, 0, len(c.QuietCodes)),
+			DevMode:            c.DevMode,
+			BuildInfo:          c.BuildInfo != nil,
+			HostInfo:           c.HostInfo != nil,
+			CustomClock:        c.Clock != nil,
+			CustomIDs:          c.IDGenerator != nil,
+			CustomSymbolizer:   c.Symbolizer != nil,
+			MaxErrorSize:       c.MaxErrorSize,
+			Hooks:              len(c.hooks),
+			RegisteredCodes:    len(c.registry),
+			CancellationPolicy: c.CancellationPolicy.String(),
+			SmallFootprint:     smallFootprint,
+			StackSampling:      c.StackSampling,
+			MaxStackDepth:      c.stackDepth(),
+			CodeStrictness:     c.CodeStrictness.String(),
+			OpLines:            c.OpLines,
+			GoroutineInfo:      c.GoroutineInfo,
+		},
+		CreatedByCode: make(map[string]int64),
+		Canceled:      atomic.LoadInt64(&canceledCount),
+		StackSamples:  make(map[string]uint64),
+	}
+	if vars.Config.ErrorTemplate == "" {
+		vars.Config.ErrorTemplate = DefaultErrorTemplate
+	}
+	for code, quiet := range c.QuietCodes {
+		if quiet {
+			vars.Config.QuietCodes = append(vars.Config.QuietCodes, string(code))
+		}
+	}
+	sort.Strings(vars.Config.QuietCodes)
+
+	createdByCode.Range(func(code, n interface{}) bool {
+		vars.CreatedByCode[string(code.(Code))] = atomic.LoadInt64(n.(*int64))
+		return true
+	})
+	stackSamples.Range(func(code, n interface{}) bool {
+		vars.StackSamples[string(code.(Code))] = atomic.LoadUint64(n.(*uint64))
+		return true
+	})
+	return vars
+}
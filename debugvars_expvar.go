@@ -0,0 +1,23 @@
+//go:build !tinygo
+// +build !tinygo
+
+package e
+
+import "expvar"
+
+// DebugVars returns an expvar.Var reporting the active Config, including stack
+// sampling and depth, and the number of error stacks created and sampled per
+// code since the process started. It is not published automatically so the
+// name is up to the caller. Settings can be changed live
+// with Configure and the setters, e.g. RegisterQuietCodes to stop capturing
+// stacks for a noisy code during an incident.
+//
+// Usage:
+//
+//	expvar.Publish("errors", e.DebugVars())
+//	// GET /debug/vars now includes {"errors": {"config": {...}, "created_by_code": {...}}}
+func DebugVars() expvar.Var {
+	return expvar.Func(func() interface{} {
+		return readDebugVars()
+	})
+}
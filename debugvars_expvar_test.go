@@ -0,0 +1,69 @@
+//go:build !tinygo
+// +build !tinygo
+
+package e
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDebugVars(t *testing.T) {
+	RegisterQuietCodes("test_debug_quiet")
+	defer UnregisterQuietCodes("test_debug_quiet")
+
+	var before debugVars
+	if err := json.Unmarshal([]byte(DebugVars().String()), &before); err != nil {
+		t.Fatalf("expected JSON but got %v", err)
+	}
+	NewError("test_debug_loud", "counted")
+	NewError("test_debug_quiet", "not counted")
+	NewError("test_debug_quiet", "not counted")
+
+	var after debugVars
+	if err := json.Unmarshal([]byte(DebugVars().String()), &after); err != nil {
+		t.Fatalf("expected JSON but got %v", err)
+	}
+	if got, want := after.Config.QuietCodes, []string{"test_debug_quiet"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+	if got := after.Config.ErrorTemplate; got != DefaultErrorTemplate {
+		t.Errorf("\ngot:  %q\nwant: %q", got, DefaultErrorTemplate)
+	}
+	for code, want := range map[string]int64{"test_debug_loud": 1, "test_debug_quiet": 0} {
+		if got := after.CreatedByCode[code] - before.CreatedByCode[code]; got != want {
+			t.Errorf("expected %d errors with code %q but got %d", want, code, got)
+		}
+	}
+}
+
+func TestDebugVarsSettings(t *testing.T) {
+	SetStackSampling(10)
+	defer SetStackSampling(0)
+	SetMaxStackDepth(8)
+	defer SetMaxStackDepth(0)
+	SetCodeStrictness(StrictnessReport)
+	defer SetCodeStrictness(StrictnessOff)
+	SetOpLines(true)
+	defer SetOpLines(false)
+	SetGoroutineInfo(true)
+	defer SetGoroutineInfo(false)
+
+	NewError(CodeInternal, "sampled")
+	NewError(CodeInternal, "not sampled")
+
+	var vars debugVars
+	if err := json.Unmarshal([]byte(DebugVars().String()), &vars); err != nil {
+		t.Fatalf("expected JSON but got %v", err)
+	}
+	got := vars.Config
+	want := debugConfig{StackSampling: 10, MaxStackDepth: 8, CodeStrictness: "report", OpLines: true, GoroutineInfo: true}
+	if got.StackSampling != want.StackSampling || got.MaxStackDepth != want.MaxStackDepth ||
+		got.CodeStrictness != want.CodeStrictness || got.OpLines != want.OpLines || got.GoroutineInfo != want.GoroutineInfo {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if vars.StackSamples[CodeInternal] < 2 {
+		t.Errorf("expected stack samples of %q to be counted but got %v", CodeInternal, vars.StackSamples)
+	}
+}
@@ -0,0 +1,44 @@
+package e
+
+import "errors"
+
+// Derive constructs a new Error like NewError which references parent without
+// wrapping it: parent is not printed by Error() and is not reachable with
+// errors.Is, errors.As or Unwrap. Use Lineage to retrieve it.
+//
+// This is useful to surface a clean user-facing error while retaining a link to
+// the internal error which triggered it for logging.
+//
+// Usage:
+//
+//	err := db.GetBar(id)
+//	if err != nil {
+//		return e.Derive(err, "not_exists", "bar does not exist")
+//	}
+func Derive(parent error, code Code, cause string) Error {
+	derived := newError(3, code, errors.New(cause))
+	derived.parent = parent
+	return derived
+}
+
+// Lineage returns the parents of err recorded by Derive, nearest first. The
+// chain of each parent is searched in turn, so errors derived from derived
+// errors return every ancestor.
+func Lineage(err error) []error {
+	var lineage []error
+	for err != nil {
+		var parent error
+		Walk(err, func(err error) bool {
+			if e, ok := err.(errorImpl); ok && e.parent != nil {
+				parent = e.parent
+				return false
+			}
+			return true
+		})
+		if parent != nil {
+			lineage = append(lineage, parent)
+		}
+		err = parent
+	}
+	return lineage
+}
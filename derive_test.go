@@ -0,0 +1,32 @@
+package e
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	skipNoCapture(t)
+
+	root := errors.New("sql: no rows")
+	internal := Wrap(root)
+	derived := Derive(internal, "not_exists", "bar does not exist")
+	public := Wrap(Derive(Wrap(derived), CodeUnexpected, "cannot show bar"))
+
+	if got, want := derived.Error(), "TestDerive: [not_exists] bar does not exist"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if errors.Is(derived, root) {
+		t.Errorf("expected derived error not to wrap parent")
+	}
+	if got := Lineage(derived); !reflect.DeepEqual(got, []error{internal}) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, []error{internal})
+	}
+	if got := Lineage(public); len(got) != 2 || got[1] != error(internal) {
+		t.Errorf("expected every ancestor but got %v", got)
+	}
+	if got := Lineage(root); got != nil {
+		t.Errorf("expected no lineage but got %v", got)
+	}
+}
@@ -0,0 +1,77 @@
+// Package ecodes provides a curated set of codes for package e aligned with
+// the canonical gRPC status codes. Importing it registers a Descriptor for every
+// code, so HTTP and gRPC integrations which use e.Describe work out of the box:
+//
+//	return e.NewError(ecodes.NotFound, "no such user")
+//
+//	d, _ := e.Describe(e.ErrorCode(err)) // d.HTTPStatus == 404, d.GRPCCode == 5
+//
+// Applications may re-register any of these codes to change their Descriptor.
+package ecodes
+
+import (
+	"net/http"
+
+	"github.com/kisunji/e"
+)
+
+// Codes aligned with google.golang.org/grpc/codes. OK has no equivalent since
+// it is not an error. Codes which package e assigns itself are aliases of its
+// built-in codes, so that errors from WrapDeadline, Ensure, context
+// cancellation and validation helpers map to the matching statuses.
+const (
+	Canceled           e.Code = e.CodeCanceled
+	Unknown            e.Code = e.CodeUnknown
+	InvalidArgument    e.Code = e.CodeValidation
+	DeadlineExceeded   e.Code = e.CodeTimeout
+	NotFound           e.Code = "not_found"
+	AlreadyExists      e.Code = "already_exists"
+	PermissionDenied   e.Code = "permission_denied"
+	ResourceExhausted  e.Code = "resource_exhausted"
+	FailedPrecondition e.Code = "failed_precondition"
+	Aborted            e.Code = "aborted"
+	OutOfRange         e.Code = "out_of_range"
+	Unimplemented      e.Code = "unimplemented"
+	Internal           e.Code = "internal"
+	Unavailable        e.Code = "unavailable"
+	DataLoss           e.Code = "data_loss"
+	Unauthenticated    e.Code = "unauthenticated"
+)
+
+// descriptors maps every code to HTTP statuses following grpc-gateway and to
+// its gRPC code number.
+var descriptors = []e.Descriptor{
+	{Code: Canceled, HTTPStatus: 499, GRPCCode: 1, Severity: e.SeverityInfo},
+	{Code: Unknown, HTTPStatus: http.StatusInternalServerError, GRPCCode: 2, Severity: e.SeverityError},
+	{Code: InvalidArgument, HTTPStatus: http.StatusBadRequest, GRPCCode: 3, Severity: e.SeverityInfo},
+	{Code: DeadlineExceeded, HTTPStatus: http.StatusGatewayTimeout, GRPCCode: 4, Severity: e.SeverityWarning},
+	{Code: NotFound, HTTPStatus: http.StatusNotFound, GRPCCode: 5, Severity: e.SeverityInfo},
+	{Code: AlreadyExists, HTTPStatus: http.StatusConflict, GRPCCode: 6, Severity: e.SeverityInfo},
+	{Code: PermissionDenied, HTTPStatus: http.StatusForbidden, GRPCCode: 7, Severity: e.SeverityInfo},
+	{Code: ResourceExhausted, HTTPStatus: http.StatusTooManyRequests, GRPCCode: 8, Severity: e.SeverityWarning, Retryable: true},
+	{Code: FailedPrecondition, HTTPStatus: http.StatusBadRequest, GRPCCode: 9, Severity: e.SeverityInfo},
+	{Code: Aborted, HTTPStatus: http.StatusConflict, GRPCCode: 10, Severity: e.SeverityWarning, Retryable: true},
+	{Code: OutOfRange, HTTPStatus: http.StatusBadRequest, GRPCCode: 11, Severity: e.SeverityInfo},
+	{Code: Unimplemented, HTTPStatus: http.StatusNotImplemented, GRPCCode: 12, Severity: e.SeverityError},
+	{Code: Internal, HTTPStatus: http.StatusInternalServerError, GRPCCode: 13, Severity: e.SeverityError},
+	{Code: Unavailable, HTTPStatus: http.StatusServiceUnavailable, GRPCCode: 14, Severity: e.SeverityWarning, Retryable: true},
+	{Code: DataLoss, HTTPStatus: http.StatusInternalServerError, GRPCCode: 15, Severity: e.SeverityCritical},
+	{Code: Unauthenticated, HTTPStatus: http.StatusUnauthorized, GRPCCode: 16, Severity: e.SeverityInfo},
+}
+
+func init() {
+	for _, d := range descriptors {
+		e.Register(d)
+	}
+}
+
+// FromGRPCCode returns the code for a gRPC code number, e.g. from
+// uint32(status.Code(err)), or Unknown if there is none.
+func FromGRPCCode(code uint32) e.Code {
+	for _, d := range descriptors {
+		if d.GRPCCode == code {
+			return d.Code
+		}
+	}
+	return Unknown
+}
@@ -0,0 +1,72 @@
+package ecodes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kisunji/e"
+)
+
+func TestDescriptors(t *testing.T) {
+	seen := make(map[uint32]bool)
+	for _, want := range descriptors {
+		got, ok := e.Describe(want.Code)
+		if !ok || got != want {
+			t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+		}
+		if seen[want.GRPCCode] {
+			t.Errorf("duplicate gRPC code %d", want.GRPCCode)
+		}
+		seen[want.GRPCCode] = true
+	}
+	if !e.IsRetryable(e.NewError(Unavailable, "try later")) {
+		t.Errorf("expected %q to be retryable", Unavailable)
+	}
+}
+
+func TestFromGRPCCode(t *testing.T) {
+	tests := []struct {
+		code uint32
+		want e.Code
+	}{
+		{code: 5, want: NotFound},
+		{code: 16, want: Unauthenticated},
+		{code: 0, want: Unknown},
+		{code: 99, want: Unknown},
+	}
+	for _, tt := range tests {
+		if got := FromGRPCCode(tt.code); got != tt.want {
+			t.Errorf("FromGRPCCode(%d) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestBuiltinCodes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantGRPC   uint32
+	}{
+		{name: "WrapDeadline", err: e.WrapDeadline(ctx, ctx.Err()), wantStatus: http.StatusGatewayTimeout, wantGRPC: 4},
+		{name: "Ensure", err: e.Ensure(errors.New("basic error")), wantStatus: http.StatusInternalServerError, wantGRPC: 2},
+		{name: "validation", err: e.NewError(e.CodeValidation, "invalid email"), wantStatus: http.StatusBadRequest, wantGRPC: 3},
+		{name: "canceled", err: e.NewError(e.CodeCanceled, "canceled"), wantStatus: 499, wantGRPC: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.HTTPStatus(tt.err); got != tt.wantStatus {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.wantStatus)
+			}
+			if got := e.GRPCCode(tt.err); got != tt.wantGRPC {
+				t.Errorf("GRPCCode() = %d, want %d", got, tt.wantGRPC)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package e
+
+// Ensure returns err unchanged if it is already an Error. Otherwise it wraps
+// err like Wrap, capturing a stack unless one exists in the chain, and sets
+// CodeUnknown if no error in the chain has a code. Returns nil for a nil error.
+//
+// Boundary layers can call it once to guarantee that every outgoing error is
+// fully formed, whatever the code below them returned.
+//
+// Usage:
+//
+//	func (s *Server) GetUser(ctx context.Context, req *GetUserRequest) (*User, error) {
+//		user, err := s.users.Get(ctx, req.ID)
+//		return user, e.Ensure(err)
+//	}
+func Ensure(err error) Error {
+	if err == nil {
+		return nil
+	}
+	if ensured, ok := err.(Error); ok {
+		return ensured
+	}
+	wrapped := wrap(err, 3)
+	if rawCode(err) == "" {
+		wrapped.code = CodeUnknown
+		wrapped = wrapped.withText()
+	}
+	return wrapped
+}
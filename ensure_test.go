@@ -0,0 +1,61 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEnsure(t *testing.T) {
+	skipNoCapture(t)
+
+	if got := Ensure(nil); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+
+	pkgErr := Foo()
+	if got := Ensure(pkgErr); got != pkgErr {
+		t.Errorf("expected Error to be returned unchanged but got %v", got)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode Code
+		wantText string
+		wantTop  string
+	}{
+		{
+			name:     "basic error",
+			err:      errors.New("basic error"),
+			wantCode: CodeUnknown,
+			wantText: "TestEnsure.func1: [unknown_error] basic error",
+			wantTop:  "TestEnsure.func1",
+		},
+		{
+			name:     "wrapped Error keeps code and stack",
+			err:      fmt.Errorf("wrapped: %w", pkgErr),
+			wantCode: CodeDatabase,
+			wantText: "TestEnsure.func1: wrapped: Foo: [database_error] cannot foo",
+			wantTop:  "Foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Ensure(tt.err)
+			if code := ErrorCode(got); code != tt.wantCode {
+				t.Errorf("expected code %q but got %q", tt.wantCode, code)
+			}
+			if text := got.Error(); text != tt.wantText {
+				t.Errorf("expected %q but got %q", tt.wantText, text)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("expected %v to wrap %v", got, tt.err)
+			}
+			frames := ErrorStackFrames(got)
+			if len(frames) == 0 || frames[0].Function != "github.com/kisunji/e."+tt.wantTop {
+				t.Errorf("expected stack starting at %s but got %v", tt.wantTop, frames)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package e
+
+import (
+	"bytes"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEncodeSigned(t *testing.T) {
+	skipNoCapture(t)
+
+	key := []byte("secret")
+	info := &BuildInfo{Path: "github.com/acme/billing", Version: "v1.2.3", Revision: "abc123", GoVersion: "go1.18"}
+	SetBuildInfo(info)
+	defer SetBuildInfo(nil)
+	err := Wrap(Foo()).
+		SetMessage("Cannot load bar.").
+		SetSuggestion("Try again later.").
+		SetHelpURL("https://example.com/database_error").
+		SetDetails(map[string]interface{}{"table": "bars"}).
+		SetRetryAfter(time.Second).
+		SetID("abc")
+
+	payload, encodeErr := EncodeSigned(err, key)
+	if encodeErr != nil {
+		t.Fatalf("unexpected error: %v", encodeErr)
+	}
+
+	decoded, decodeErr := DecodeSigned(payload, key)
+	if decodeErr != nil {
+		t.Fatalf("unexpected error: %v", decodeErr)
+	}
+	got := map[string]interface{}{
+		"code":        ErrorCode(decoded),
+		"message":     ErrorMessage(decoded),
+		"suggestion":  ErrorSuggestion(decoded),
+		"help_url":    ErrorHelpURL(decoded),
+		"details":     ErrorDetails(decoded),
+		"retry_after": ErrorRetryAfter(decoded),
+		"error_id":    ErrorID(decoded),
+		"build_info":  ErrorBuildInfo(decoded),
+	}
+	want := map[string]interface{}{
+		"code":        Code(CodeDatabase),
+		"message":     "Cannot load bar.",
+		"suggestion":  "Try again later.",
+		"help_url":    "https://example.com/database_error",
+		"details":     map[string]interface{}{"table": "bars"},
+		"retry_after": time.Second,
+		"error_id":    "abc",
+		"build_info":  info,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+	if got := decoded.Error(); got != "TestEncodeSigned: [database_error] Cannot load bar." {
+		t.Errorf("unexpected Error() %q", got)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		key  []byte
+	}{
+		{name: "wrong key", data: payload, key: []byte("other")},
+		{name: "tampered payload", data: bytes.Replace(payload, []byte(CodeDatabase), []byte(CodeInternal), 1), key: key},
+		{name: "malformed", data: []byte("{"), key: key},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeSigned(tt.data, tt.key)
+			if decoded != nil || !IsCode(err, CodeValidation) {
+				t.Errorf("expected validation error but got %v, %v", decoded, err)
+			}
+		})
+	}
+
+	if _, err := EncodeSigned(nil, key); err == nil {
+		t.Errorf("expected error for nil error")
+	}
+}
+
+func TestEncodeSignedEmptyKey(t *testing.T) {
+	if _, err := EncodeSigned(Foo(), nil); !IsCode(err, CodeValidation) {
+		t.Errorf("expected validation error but got %v", err)
+	}
+	payload, err := EncodeSigned(Foo(), []byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded, err := DecodeSigned(payload, []byte{}); decoded != nil || !IsCode(err, CodeValidation) {
+		t.Errorf("expected validation error but got %v, %v", decoded, err)
+	}
+}
+
+func TestDecodeSignedRemoteError(t *testing.T) {
+	key := []byte("secret")
+	SetCodeNamespace("billing")
+	defer SetCodeNamespace("")
+	payload, err := EncodeSigned(NewError("card_declined", "cannot charge"), key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var hooked []Error
+	remove := AddHook(func(err Error) { hooked = append(hooked, err) })
+	defer remove()
+	SetCodeStrictness(StrictnessPanic)
+	defer SetCodeStrictness(StrictnessOff)
+
+	recorded := atomic.LoadUint64(&recentNext)
+	decoded, err := DecodeSigned(payload, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := ErrorCode(decoded), Code("billing.card_declined"); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if len(hooked) != 0 {
+		t.Errorf("expected no hooks or violations for a remote error but got %v", hooked)
+	}
+	if atomic.LoadUint64(&recentNext) != recorded {
+		t.Errorf("expected remote error not to be recorded by Recent")
+	}
+}
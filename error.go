@@ -5,9 +5,7 @@ package e
 import (
 	"errors"
 	"fmt"
-	"runtime"
-	"runtime/debug"
-	"strings"
+	"time"
 )
 
 // Error represents a standard application error.
@@ -24,13 +22,82 @@ type Error interface {
 	// "database_error", "not_exists", etc.
 	//
 	// Will panic when used with a nil Error receiver.
-	SetCode(code string) Error
+	SetCode(code Code) Error
 
 	// SetMessage adds a user-friendly message to a non-nil Error.
 	// Message will not be printed with Error() and should be retrieved with ErrorMessage().
 	//
 	// Will panic when used with a nil Error receiver.
 	SetMessage(message string) Error
+
+	// SetSuggestion adds a user-friendly remediation to a non-nil Error, telling
+	// users what to do next while the message tells them what happened.
+	// Suggestion will not be printed with Error() and should be retrieved with
+	// ErrorSuggestion().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetSuggestion(suggestion string) Error
+
+	// SetDetails adds structured client-facing details to a non-nil Error, such
+	// as per-field validation errors or quota info, to be returned alongside the
+	// code and message. Details will not be printed with Error() and should be
+	// retrieved with ErrorDetails().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetDetails(details interface{}) Error
+
+	// SetGroupKey overrides the key used to group occurrences of a non-nil
+	// Error, e.g. to group all per-tenant variations of a config error together.
+	// Retrieve it with ErrorGroupKey().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetGroupKey(key string) Error
+
+	// SetHelpURL links a non-nil Error to a runbook or documentation page for
+	// users or operators, overriding the HelpURL registered for its code.
+	// Retrieve it with ErrorHelpURL().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetHelpURL(url string) Error
+
+	// SetID overrides the correlation ID of a non-nil Error, which is otherwise
+	// generated when a new error stack is created. Retrieve it with ErrorID().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetID(id string) Error
+
+	// SetRetryable marks whether the operation which returned a non-nil Error
+	// may succeed if it is attempted again, overriding the registered
+	// Descriptor of its code. Check it with IsRetryable().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetRetryable(retryable bool) Error
+
+	// SetRetryAfter attaches how long callers should wait before retrying a
+	// non-nil Error, e.g. from the Retry-After header of a throttled upstream.
+	// Retrieve it with ErrorRetryAfter().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetRetryAfter(d time.Duration) Error
+
+	// SetField attaches a structured key/value pair to a non-nil Error, such as
+	// a request or entity ID, for use by log pipelines. Fields are not printed
+	// with Error() and should be retrieved with ErrorFields().
+	//
+	// Will panic when used with a nil Error receiver.
+	SetField(key string, value interface{}) Error
+
+	// SetFields attaches every key/value pair of fields like SetField.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetFields(fields map[string]interface{}) Error
+
+	// SetNoStackExport prevents the stacktrace of a non-nil Error from leaving
+	// the process. Encoders should check StackExportable() before including it.
+	// ErrorStacktrace() is unaffected.
+	//
+	// Will panic when used with a nil Error receiver.
+	SetNoStackExport() Error
 }
 
 // NewError constructs a new Error. code should be a short, single string
@@ -45,18 +112,15 @@ type Error interface {
 //			return doFoo(bar)
 //		}
 //
-func NewError(code, cause string) Error {
-	return errorImpl{
-		op:         getCallingFunc(2),
-		code:       code,
-		err:        errors.New(cause),
-		stacktrace: string(debug.Stack()),
-	}
+func NewError(code Code, cause string) Error {
+	return newError(3, code, errors.New(cause))
 }
 
 // NewErrorf constructs a new Error with formatted string. code should be a short,
 // single string describing the type of error (typically a pre-defined const).
 // cause is used to create the nested error which will act as the root of the error stack.
+// Formatting mistakes such as "%!w(MISSING)" in the cause are reported as
+// contract violations: passed to hooks, or panicking when SetDevMode is enabled.
 //
 // Usage:
 // 		func Foo(bar Bar) error {
@@ -67,13 +131,61 @@ func NewError(code, cause string) Error {
 //			return nil
 //		}
 //
-func NewErrorf(code, fmtCause string, args ...interface{}) Error {
-	return errorImpl{
-		op:         getCallingFunc(2),
-		code:       code,
-		err:        fmt.Errorf(fmtCause, args...),
-		stacktrace: string(debug.Stack()),
+func NewErrorf(code Code, fmtCause string, args ...interface{}) Error {
+	e := newError(3, code, fmt.Errorf(fmtCause, args...))
+	checkFormat(e.op, e.err.Error())
+	return e
+}
+
+// newError is the shared implementation of constructors which start a new
+// error stack. frameOffset is passed to getCallingFunc, so it must count the
+// frame of newError itself.
+func newError(frameOffset int, code Code, cause error) errorImpl {
+	return newErrorIn(loadConfig(), frameOffset+1, code, cause)
+}
+
+// newErrorIn is newError using the settings of c rather than the active Config.
+func newErrorIn(c *Config, frameOffset int, code Code, cause error) errorImpl {
+	e := errorImpl{
+		op:   c.callingOp(frameOffset),
+		code: code,
+		err:  cause,
+		text: new(errorText),
+	}
+	c.validateCode(e.op, code)
+	if c.QuietCodes[code] {
+		return e.withOrigin(c)
+	}
+	e = e.withStack(c, frameOffset)
+	e = e.withOrigin(c)
+	recordOrigin(e)
+	c.notifyHooks(e)
+	return e
+}
+
+// withOrigin stamps an Error which starts a new error stack with details about
+// where it was created.
+func (e errorImpl) withOrigin(c *Config) errorImpl {
+	e.id = c.newID()
+	e.created = c.now()
+	e.buildInfo = c.BuildInfo
+	if c.scoped {
+		e.config = c
+	}
+	if c.HostInfo != nil {
+		e = e.withHostInfo(c.HostInfo)
 	}
+	if c.GoroutineInfo {
+		e = e.withGoroutine()
+	}
+	return e
+}
+
+// recordOrigin records e, which starts a new error stack, for DebugVars and
+// Recent. It is not called for quiet codes.
+func recordOrigin(e errorImpl) {
+	countCreated(e.code)
+	recordRecent(e)
 }
 
 // Wrap adds the name of the calling function to the wrapped error.
@@ -102,21 +214,12 @@ func Wrap(err error, optionalInfo ...string) Error {
 		innerErr = fmt.Errorf("(%v): %w", optionalInfo[0], err) // localizer.Ignore
 	}
 
-	wrapped := errorImpl{
-		op:         getCallingFunc(2),
-		err:        innerErr,
-		stacktrace: ErrorStacktrace(err),
-	}
-
-	if wrapped.stacktrace == "" {
-		wrapped.stacktrace = string(debug.Stack())
-	}
-
-	return wrapped
+	return wrap(innerErr, 3)
 }
 
 // Wrapf adds the name of the calling function and a formatted message
-// to the wrapped error.
+// to the wrapped error. Formatting mistakes in the message are reported like
+// NewErrorf.
 //
 // Basic usage:
 // 		err := Foo(bar)
@@ -129,14 +232,42 @@ func Wrapf(err error, fmtInfo string, args ...interface{}) Error {
 		return nil
 	}
 
+	info := fmt.Sprintf(fmtInfo, args...)
+	wrapped := wrap(fmt.Errorf("(%v): %w", info, err), 3) // localizer.Ignore
+	checkFormat(wrapped.op, info)
+	return wrapped
+}
+
+// wrap is the shared implementation of the Wrap family. frameOffset is passed
+// to getCallingFunc, so it must count the frame of wrap itself.
+// The innermost stacktrace of err is reused if there is one. Errors with a
+// quiet code are never given one.
+func wrap(err error, frameOffset int) errorImpl {
+	return wrapIn(loadConfig(), err, frameOffset+1)
+}
+
+// wrapIn is wrap using the settings of c rather than the active Config. An
+// Error wrapped without a scoped Config keeps rendering with the scoped Config
+// of err, if any.
+func wrapIn(c *Config, err error, frameOffset int) errorImpl {
+	stack, hasStack := innermostStack(err)
 	wrapped := errorImpl{
-		op:         getCallingFunc(2),
-		err:        fmt.Errorf("(%v): %w", fmt.Sprintf(fmtInfo, args...), err), // localizer.Ignore
-		stacktrace: ErrorStacktrace(err),
+		op:    c.callingOp(frameOffset),
+		err:   err,
+		stack: stack,
+		text:  new(errorText),
+	}
+	if c.scoped {
+		wrapped.config = c
+	} else if inner, ok := err.(errorImpl); ok {
+		wrapped.config = inner.config
 	}
 
-	if wrapped.stacktrace == "" {
-		wrapped.stacktrace = string(debug.Stack())
+	if !hasStack && !c.QuietCodes[rawCode(err)] {
+		wrapped = wrapped.withStack(c, frameOffset)
+		wrapped = wrapped.withOrigin(c)
+		recordOrigin(wrapped)
+		c.notifyHooks(wrapped)
 	}
 
 	return wrapped
@@ -151,31 +282,130 @@ type errorImpl struct {
 	// Represents the error type to be used by client or application.
 	// e.g. "unexpected_error", "database_error", "not_exists" etc.
 	// Use ErrorCode(err) to retrieve the outermost code.
-	code string
+	code Code
 
 	// A user-friendly error message. Does not get printed with Error().
 	// Use ErrorMessage(err) to retrieve the outermost message.
 	message string
 
+	// A user-friendly remediation. Does not get printed with Error().
+	// Use ErrorSuggestion(err) to retrieve the outermost suggestion.
+	suggestion string
+
+	// Structured client-facing details. Does not get printed with Error().
+	// Use ErrorDetails(err) to retrieve the outermost details.
+	// Boxed so that uncomparable details cannot make errors.Is panic.
+	details *detailsBox
+
+	// Overrides the computed grouping of this error. Does not get printed with Error().
+	// Use ErrorGroupKey(err) to retrieve the outermost group key.
+	groupKey string
+
+	// Link to a runbook or documentation page. Does not get printed with Error().
+	// Use ErrorHelpURL(err) to retrieve the outermost help URL.
+	helpURL string
+
+	// Structured data for log pipelines. Does not get printed with Error().
+	// Use ErrorFields(err) to merge the fields of the whole chain.
+	// A pointer keeps errorImpl comparable for errors.Is.
+	fields *fieldMap
+
+	// Strongly-typed values attached with WithValue. Use Value(err, key) to
+	// retrieve them. A pointer keeps errorImpl comparable for errors.Is.
+	values *valueMap
+
+	// Typed attributes of this hop, set with WrapMeta. Use MetaOf(err) to
+	// retrieve it.
+	meta *Meta
+
+	// Error this error was derived from with Derive. Not part of the chain.
+	// Use Lineage(err) to retrieve it.
+	parent error
+
 	// Nested error for building an error stacktrace. Should not be nil.
 	err error
 
-	// Internal stacktrace for logging. Does not get printed with Error().
-	// Use ErrorStacktrace(err) to retrieve the innermost stacktrace.
-	stacktrace string
+	// Innermost stack, for logging. Does not get printed with Error().
+	// Use ErrorStackFrames(err) or ErrorStacktrace(err) to retrieve the
+	// innermost frames or their text. A pointer keeps errorImpl comparable
+	// for errors.Is.
+	stack *stack
+
+	// Prevents the stacktrace from being exported by encoders.
+	// Use StackExportable(err) to check the whole chain.
+	noStackExport bool
+
+	// Whether the failed operation may be retried, if set with SetRetryable.
+	// Use IsRetryable(err) to check the whole chain. A pointer distinguishes
+	// unset from false.
+	retryable *bool
+
+	// How long to wait before retrying. Does not get printed with Error().
+	// Use ErrorRetryAfter(err) to retrieve the outermost value.
+	retryAfter time.Duration
+
+	// Correlation ID of this occurrence. Generated for new error stacks.
+	// Use ErrorID(err) to retrieve the outermost ID.
+	id string
+
+	// Time the error stack was created, from the clock set with SetClock.
+	// Use ErrorTime(err) to retrieve the innermost time.
+	created time.Time
+
+	// Build which created the error, if stamping is enabled with SetBuildInfo.
+	// Use ErrorBuildInfo(err) to retrieve it.
+	buildInfo *BuildInfo
+
+	// Scoped Config the error was created with by WithConfig, used by Error().
+	// nil means the active Config.
+	config *Config
+
+	// Cache of the text rendered by Error(), shared by the copies returned by
+	// SetX methods. Must be replaced with withText whenever op, code or err
+	// change. nil disables caching.
+	text *errorText
 }
 
+// Error renders the op, code and cause of e with the template of its Config.
+// The text is cached, so calling Error() repeatedly, as log pipelines do, is
+// cheap. The text of errors not created by this package is assumed not to
+// change.
 func (e errorImpl) Error() string {
-	var sb strings.Builder
+	c := e.config
+	if c == nil {
+		c = loadConfig()
+	}
+	if e.text == nil {
+		return e.render(c)
+	}
+	if r, ok := e.text.rendered.Load().(renderedText); ok && r.config == c {
+		return r.text
+	}
+	text := e.render(c)
+	e.text.rendered.Store(renderedText{config: c, text: text})
+	return text
+}
+
+// render builds the text of Error() with the template of c.
+func (e errorImpl) render(c *Config) string {
+	if tmpl := c.errorTmpl; tmpl != nil {
+		return tmpl.render(e)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
 	if e.op != "" {
-		sb.WriteString(fmt.Sprintf("%s: ", e.op))
+		buf.WriteString(e.op)
+		buf.WriteString(": ")
 	}
 	if e.code != "" {
-		sb.WriteString(fmt.Sprintf("[%s] ", e.code)) // localizer.Ignore
+		buf.WriteString("[") // localizer.Ignore
+		buf.WriteString(string(e.code))
+		buf.WriteString("] ")
 	}
-	sb.WriteString(e.err.Error())
+	buf.WriteString(causeString(e.err))
 
-	return sb.String()
+	return buf.String()
 }
 
 func (e errorImpl) Unwrap() error {
@@ -183,16 +413,17 @@ func (e errorImpl) Unwrap() error {
 }
 
 func (e errorImpl) ClientCode() string {
-	return e.code
+	return string(e.code)
 }
 
 func (e errorImpl) ClientMessage() string {
 	return e.message
 }
 
-func (e errorImpl) SetCode(code string) Error {
+func (e errorImpl) SetCode(code Code) Error {
+	loadConfig().validateCode(e.op, code)
 	e.code = code
-	return e
+	return e.withText()
 }
 
 func (e errorImpl) SetMessage(message string) Error {
@@ -200,25 +431,68 @@ func (e errorImpl) SetMessage(message string) Error {
 	return e
 }
 
+func (e errorImpl) SetSuggestion(suggestion string) Error {
+	e.suggestion = suggestion
+	return e
+}
+
+func (e errorImpl) ClientSuggestion() string {
+	return e.suggestion
+}
+
+func (e errorImpl) SetDetails(details interface{}) Error {
+	e.details = &detailsBox{details}
+	checkSize(e)
+	return e
+}
+
+func (e errorImpl) ClientDetails() interface{} {
+	if e.details == nil {
+		return nil
+	}
+	return e.details.v
+}
+
+func (e errorImpl) SetGroupKey(key string) Error {
+	e.groupKey = key
+	return e
+}
+
+func (e errorImpl) GroupKey() string {
+	return e.groupKey
+}
+
+func (e errorImpl) SetHelpURL(url string) Error {
+	e.helpURL = url
+	return e
+}
+
+func (e errorImpl) HelpURL() string {
+	return e.helpURL
+}
+
+func (e errorImpl) SetNoStackExport() Error {
+	e.noStackExport = true
+	return e
+}
+
 func (e errorImpl) Stacktrace() string {
-	return e.stacktrace
-}
-
-// getCallingFunc returns the name of the calling function N levels
-// above getCallingFunc (e.g. 0 for `getCallingFunc` itself)
-func getCallingFunc(frameOffset int) string {
-	// only need len = 1 to contain the calling function
-	programCounters := make([]uintptr, 1)
-	// base offset is 1 to skip `runtime.Callers` itself
-	n := runtime.Callers(1+frameOffset, programCounters)
-	if n == 0 {
-		return "unknown"
-	}
-	frames := runtime.CallersFrames(programCounters)
-	frame, _ := frames.Next()
-
-	// Remove package name (too verbose)
-	ss := strings.Split(frame.Function, "/")
-	funcname := ss[len(ss)-1]
-	return strings.SplitAfterN(funcname, ".", 2)[1]
+	frames := e.StackFrames()
+	if len(frames) == 0 {
+		return ""
+	}
+	return loadConfig().stackFormatter().FormatStack(frames)
+}
+
+// ErrorOps returns the op of every Error in the chain, outermost first. This is
+// the logical stack printed by Error() without codes or causes.
+func ErrorOps(err error) []string {
+	var ops []string
+	for err != nil {
+		if e, ok := err.(errorImpl); ok && e.op != "" {
+			ops = append(ops, e.op)
+		}
+		err = errors.Unwrap(err)
+	}
+	return ops
 }
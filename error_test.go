@@ -15,6 +15,8 @@ const (
 var errSentinel = NewError(CodeInternal, "sentinel error")
 
 func TestErrors(t *testing.T) {
+	skipNoCapture(t)
+
 	tests := []struct {
 		name string
 		fn   func() error
@@ -223,12 +225,12 @@ func TestErrorMessage(t *testing.T) {
 func TestErrorCode(t *testing.T) {
 	tests := []struct {
 		name string
-		fn   func() string
-		want string
+		fn   func() Code
+		want Code
 	}{
 		{
 			name: "unset code returns blank",
-			fn: func() string {
+			fn: func() Code {
 				err := NewError("", "unexpected error occurred")
 				return ErrorCode(err)
 			},
@@ -236,7 +238,7 @@ func TestErrorCode(t *testing.T) {
 		},
 		{
 			name: "set code with NewError() returns correctly",
-			fn: func() string {
+			fn: func() Code {
 				err := NewError(CodeUnexpected, "unexpected error occurred")
 				return ErrorCode(err)
 			},
@@ -244,7 +246,7 @@ func TestErrorCode(t *testing.T) {
 		},
 		{
 			name: "set code with Wrap() returns correctly",
-			fn: func() string {
+			fn: func() Code {
 				err := errors.New("db error occurred")
 				wrap := Wrap(err).SetCode(CodeDatabase)
 				return ErrorCode(wrap)
@@ -253,7 +255,7 @@ func TestErrorCode(t *testing.T) {
 		},
 		{
 			name: "setting multiple codes but last code is returned",
-			fn: func() string {
+			fn: func() Code {
 				err1 := NewError(CodeUnexpected, "bar")
 
 				err2 := Wrap(err1).SetCode(CodeInternal)
@@ -264,7 +266,7 @@ func TestErrorCode(t *testing.T) {
 		},
 		{
 			name: "returns outermost code",
-			fn: func() string {
+			fn: func() Code {
 				err1 := NewError(CodeUnexpected, "bar")
 
 				err2 := Wrap(err1).SetCode(CodeInternal)
@@ -275,7 +277,7 @@ func TestErrorCode(t *testing.T) {
 		},
 		{
 			name: "works with non-pkg wrapping",
-			fn: func() string {
+			fn: func() Code {
 				err := NewError(CodeInternal, "cannot do something")
 
 				err = Wrap(err)
@@ -298,7 +300,229 @@ func TestErrorCode(t *testing.T) {
 	}
 }
 
+func TestErrorSuggestion(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() string
+		want string
+	}{
+		{
+			name: "unset suggestion returns blank",
+			fn: func() string {
+				return ErrorSuggestion(Foo())
+			},
+			want: "",
+		},
+		{
+			name: "outermost suggestion is returned separately from message",
+			fn: func() string {
+				err := Wrap(Foo()).SetMessage("cannot save").SetSuggestion("don't show this")
+				err = Wrap(err).SetSuggestion("try again later")
+				return ErrorMessage(err) + " / " + ErrorSuggestion(err)
+			},
+			want: "cannot save / try again later",
+		},
+		{
+			name: "works with non-pkg wrapping",
+			fn: func() string {
+				err := Wrap(Foo()).SetSuggestion("check your input")
+				return ErrorSuggestion(Wrap(fmt.Errorf("not encouraged but compatible: %w", err)))
+			},
+			want: "check your input",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorCodeOrMessageOr(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    Code
+		wantMessage string
+	}{
+		{
+			name:        "non-pkg error uses fallbacks",
+			err:         errors.New("basic error"),
+			wantCode:    CodeUnexpected,
+			wantMessage: "Something went wrong",
+		},
+		{
+			name:        "set values are returned",
+			err:         Wrap(Foo()).SetMessage("oh no"),
+			wantCode:    CodeDatabase,
+			wantMessage: "oh no",
+		},
+		{
+			name:        "missing message uses fallback",
+			err:         Foo(),
+			wantCode:    CodeDatabase,
+			wantMessage: "Something went wrong",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCodeOr(tt.err, CodeUnexpected); got != tt.wantCode {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.wantCode)
+			}
+			if got := ErrorMessageOr(tt.err, "Something went wrong"); got != tt.wantMessage {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestErrorRootCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{
+			name: "non-pkg error returns blank",
+			err:  errors.New("basic error"),
+			want: "",
+		},
+		{
+			name: "returns innermost code",
+			err:  Wrap(Wrap(Foo()).SetCode(CodeInternal)).SetCode(CodeUnexpected),
+			want: CodeDatabase,
+		},
+		{
+			name: "works with non-pkg wrapping",
+			err:  Wrap(fmt.Errorf("fmt: %w", Wrap(errors.New("basic error")).SetCode(CodeInternal))).SetCode(CodeUnexpected),
+			want: CodeInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorRootCode(tt.err); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type fieldViolation struct {
+	Field  string
+	Reason string
+}
+
+var errDetailsSentinel = NewError(CodeUnexpected, "sentinel error")
+
+func TestErrorDetails(t *testing.T) {
+	details := []fieldViolation{{Field: "age", Reason: "must be positive"}}
+
+	if got := ErrorDetails(Foo()); got != nil {
+		t.Errorf("expected no details but got %v", got)
+	}
+
+	inner := Wrap(errDetailsSentinel).SetDetails("inner")
+	err := Wrap(fmt.Errorf("fmt: %w", inner)).SetDetails(details)
+	got, ok := ErrorDetails(err).([]fieldViolation)
+	if !ok || len(got) != 1 || got[0] != details[0] {
+		t.Errorf("\ngot:  %v\nwant: %v", ErrorDetails(err), details)
+	}
+	if !errors.Is(err, errDetailsSentinel) {
+		t.Errorf("expected errors.Is to work with uncomparable details")
+	}
+}
+
+func TestErrorGroupKey(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() string
+		want string
+	}{
+		{
+			name: "unset group key returns blank",
+			fn: func() string {
+				err := NewError(CodeUnexpected, "unexpected error occurred")
+				return ErrorGroupKey(err)
+			},
+			want: "",
+		},
+		{
+			name: "returns outermost group key",
+			fn: func() string {
+				err1 := NewError(CodeInternal, "tenant 42 misconfigured").SetGroupKey("inner")
+
+				err2 := Wrap(err1).SetGroupKey("tenant_config")
+
+				return ErrorGroupKey(err2)
+			},
+			want: "tenant_config",
+		},
+		{
+			name: "works with non-pkg wrapping",
+			fn: func() string {
+				err := NewError(CodeInternal, "cannot do something").SetGroupKey("grouped")
+
+				wrap := fmt.Errorf("not encouraged but compatible: %w", err)
+
+				return ErrorGroupKey(Wrap(wrap))
+			},
+			want: "grouped",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorHelpURL(t *testing.T) {
+	Register(Descriptor{Code: "test_quota_exceeded", HelpURL: "https://example.com/quota"})
+
+	tests := []struct {
+		name string
+		fn   func() string
+		want string
+	}{
+		{
+			name: "unset help URL returns blank",
+			fn: func() string {
+				return ErrorHelpURL(Foo())
+			},
+			want: "",
+		},
+		{
+			name: "returns outermost help URL",
+			fn: func() string {
+				err := Wrap(Foo()).SetHelpURL("https://example.com/inner")
+				return ErrorHelpURL(Wrap(err).SetHelpURL("https://example.com/outer"))
+			},
+			want: "https://example.com/outer",
+		},
+		{
+			name: "falls back to registered code",
+			fn: func() string {
+				return ErrorHelpURL(Wrap(NewError("test_quota_exceeded", "too many requests")))
+			},
+			want: "https://example.com/quota",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestErrorStack(t *testing.T) {
+	skipNoCapture(t)
+
 	t.Run("ErrorStacktrace returns something", func(t *testing.T) {
 		err := NewError("", "unexpected error occurred")
 		if ErrorStacktrace(err) == "" {
@@ -308,13 +532,13 @@ func TestErrorStack(t *testing.T) {
 	t.Run("ErrorStacktrace returns inner stacktrace", func(t *testing.T) {
 		err := NewError("", "unexpected error occurred")
 		badError := errorImpl{
-			op:         "BAD",
-			code:       "BAD",
-			message:    "BAD",
-			err:        err,
-			stacktrace: "BAD",
+			op:      "BAD",
+			code:    "BAD",
+			message: "BAD",
+			err:     err,
+			stack:   &stack{pcs: capturePCs(0, defaultStackDepth)},
 		}
-		if ErrorStacktrace(badError) == "BAD" {
+		if ErrorStacktrace(badError) != err.Stacktrace() {
 			t.Fatalf("expected inner stacktrace from ErrorStacktrace() but got outer")
 		}
 	})
@@ -327,6 +551,8 @@ func Benchmark_getCallingFunc(b *testing.B) {
 }
 
 func Test_getCallingFunc(t *testing.T) {
+	skipNoCapture(t)
+
 	tests := []struct {
 		name        string
 		frameOffset int
@@ -355,4 +581,17 @@ func Test_getCallingFunc(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestHelper(t *testing.T) {
+	skipNoCapture(t)
+
+	err := helperWrap(Foo())
+	if got, want := err.Error(), "TestHelper: [database_error] Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func helperWrap(err error) error {
+	Helper()
+	return Wrap(err).SetCode(CodeDatabase)
+}
@@ -0,0 +1,49 @@
+package e
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Errorf is like NewErrorf but takes the code from a leading "[code]" token of
+// format, mirroring how Error() renders codes, so fmt.Errorf calls can be
+// migrated by changing the package name. The cause may wrap other errors with
+// %w. Without a leading code the Error has no code.
+//
+// Usage:
+//
+//	if err != nil {
+//		return e.Errorf("[database_error] query failed for id %d: %w", id, err)
+//	}
+func Errorf(format string, args ...interface{}) Error {
+	code, format := cutCodePrefix(format)
+	e := newError(3, code, fmt.Errorf(format, args...))
+	checkFormat(e.op, e.err.Error())
+	return e
+}
+
+// cutCodePrefix splits a leading "[code]" token and the spaces following it
+// from format. Brackets holding anything but a code, such as "[id %d]", are
+// left alone as part of the message.
+func cutCodePrefix(format string) (code Code, rest string) {
+	if !strings.HasPrefix(format, "[") {
+		return "", format
+	}
+	end := strings.IndexByte(format, ']')
+	if end < 2 {
+		return "", format
+	}
+	code = Code(format[1:end])
+	for _, r := range code {
+		if !isCodeRune(r) {
+			return "", format
+		}
+	}
+	return code, strings.TrimLeft(format[end+1:], " ")
+}
+
+// isCodeRune reports whether r may appear in a code parsed by Errorf.
+func isCodeRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == '_' || r == '.' || r == '-'
+}
@@ -0,0 +1,63 @@
+package e
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestErrorf(t *testing.T) {
+	skipNoCapture(t)
+
+	tests := []struct {
+		name     string
+		err      Error
+		wantCode Code
+		wantText string
+	}{
+		{
+			name:     "parses leading code",
+			err:      Errorf("[database_error] query failed for id %d: %w", 1, io.EOF),
+			wantCode: CodeDatabase,
+			wantText: "TestErrorf: [database_error] query failed for id 1: EOF",
+		},
+		{
+			name:     "hierarchical code",
+			err:      Errorf("[database.connection] cannot connect"),
+			wantCode: "database.connection",
+			wantText: "TestErrorf: [database.connection] cannot connect",
+		},
+		{
+			name:     "no code",
+			err:      Errorf("query failed: %w", io.EOF),
+			wantText: "TestErrorf: query failed: EOF",
+		},
+		{
+			name:     "brackets which are not a code",
+			err:      Errorf("[id %d] not found", 1),
+			wantText: "TestErrorf: [id 1] not found",
+		},
+		{
+			name:     "empty brackets",
+			err:      Errorf("[] empty"),
+			wantText: "TestErrorf: [] empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCode(tt.err); got != tt.wantCode {
+				t.Errorf("expected code %q but got %q", tt.wantCode, got)
+			}
+			if got := tt.err.Error(); got != tt.wantText {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.wantText)
+			}
+			if ErrorStacktrace(tt.err) == "" {
+				t.Errorf("expected stacktrace")
+			}
+		})
+	}
+
+	if err := Errorf("[database_error] query failed: %w", io.EOF); !errors.Is(err, io.EOF) {
+		t.Errorf("expected %%w to wrap the cause")
+	}
+}
@@ -0,0 +1,75 @@
+// Package etest provides helpers for asserting on errors created with package e
+// in tests.
+package etest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+// Recorder records every Error created while it is installed.
+type Recorder struct {
+	mu   sync.Mutex
+	errs []e.Error
+}
+
+// CaptureErrors installs a hook which records every Error created until the end
+// of the test, including errors which are logged but never returned. Hooks are
+// global, so errors created by parallel tests are recorded too.
+//
+// Usage:
+//
+//	func TestHandler(t *testing.T) {
+//		rec := etest.CaptureErrors(t)
+//		handler.ServeHTTP(w, r)
+//		if len(rec.ByCode("database_error")) != 0 {
+//			t.Errorf("unexpected database error")
+//		}
+//	}
+func CaptureErrors(t testing.TB) *Recorder {
+	r := &Recorder{}
+	remove := e.AddHook(r.record)
+	t.Cleanup(remove)
+	return r
+}
+
+func (r *Recorder) record(err e.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+// All returns every recorded Error in order of creation.
+func (r *Recorder) All() []e.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]e.Error(nil), r.errs...)
+}
+
+// ByCode returns the recorded errors whose ErrorCode is code.
+func (r *Recorder) ByCode(code e.Code) []e.Error {
+	return r.filter(func(err e.Error) bool {
+		return e.ErrorCode(err) == code
+	})
+}
+
+// ByOp returns the recorded errors created by the function named op, e.g.
+// "Foo" or "(*Repo).Get".
+func (r *Recorder) ByOp(op string) []e.Error {
+	return r.filter(func(err e.Error) bool {
+		ops := e.ErrorOps(err)
+		return len(ops) > 0 && ops[0] == op
+	})
+}
+
+func (r *Recorder) filter(keep func(e.Error) bool) []e.Error {
+	var errs []e.Error
+	for _, err := range r.All() {
+		if keep(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,39 @@
+//go:build !e_small && !e_noop && !tinygo && !js
+// +build !e_small,!e_noop,!tinygo,!js
+
+package etest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+func TestCaptureErrors(t *testing.T) {
+	var rec *Recorder
+	t.Run("records during test", func(t *testing.T) {
+		rec = CaptureErrors(t)
+		logged()
+		e.Wrap(errors.New("basic error"))
+	})
+	e.NewError("not_recorded", "created after cleanup")
+
+	if got := len(rec.All()); got != 2 {
+		t.Fatalf("expected 2 errors but got %d", got)
+	}
+	if got := rec.ByCode("database_error"); len(got) != 1 {
+		t.Errorf("expected 1 database_error but got %v", got)
+	}
+	if got := rec.ByOp("logged"); len(got) != 1 {
+		t.Errorf("expected 1 error from logged but got %v", got)
+	}
+	if got := rec.ByCode("not_recorded"); len(got) != 0 {
+		t.Errorf("expected hook to be removed but got %v", got)
+	}
+}
+
+func logged() {
+	err := e.NewError("database_error", "cannot foo")
+	_ = err // logged but not returned
+}
@@ -0,0 +1,32 @@
+package e
+
+import "fmt"
+
+// WrapExpect wraps err like Wrap and reports a contract violation when the code
+// of err is not one of expectedCodes, catching drift between layers, e.g. a
+// repository which should only ever return "not_exists" or "database_error".
+//
+// Violations are passed to hooks as an Error with code CodeContractViolation,
+// or panic when SetDevMode is enabled. The returned Error is the same either way.
+//
+// Usage:
+//
+//	bar, err := repo.GetBar(id)
+//	if err != nil {
+//		return e.WrapExpect(err, "not_exists", "database_error")
+//	}
+func WrapExpect(err error, expectedCodes ...Code) Error {
+	if err == nil {
+		return nil
+	}
+
+	wrapped := wrap(err, 3)
+	code := rawCode(err)
+	for _, expected := range expectedCodes {
+		if code == expected {
+			return wrapped
+		}
+	}
+	reportViolation(wrapped.op, fmt.Sprintf("unexpected code %q, expected one of %q", code, expectedCodes))
+	return wrapped
+}
@@ -0,0 +1,47 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWrapExpect(t *testing.T) {
+	skipNoCapture(t)
+	skipSmallFootprint(t)
+
+	var violations []Error
+	remove := AddHook(func(err Error) {
+		if ErrorCode(err) == CodeContractViolation {
+			violations = append(violations, err)
+		}
+	})
+	defer remove()
+
+	err := WrapExpect(Foo(), CodeDatabase, "not_exists")
+	if got, want := err.Error(), "TestWrapExpect: Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations but got %v", violations)
+	}
+
+	WrapExpect(errors.New("basic error"), CodeDatabase)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation but got %v", violations)
+	}
+	want := `TestWrapExpect: [contract_violation] unexpected code "", expected one of ["database_error"]`
+	if got := violations[0].Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+
+	t.Run("panics in dev mode", func(t *testing.T) {
+		SetDevMode(true)
+		defer SetDevMode(false)
+		defer func() {
+			if err, _ := recover().(Error); ErrorCode(err) != CodeContractViolation {
+				t.Errorf("expected contract violation panic but got %v", err)
+			}
+		}()
+		WrapExpect(Foo(), CodeInternal)
+	})
+}
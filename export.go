@@ -0,0 +1,33 @@
+package e
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// ExportNDJSON writes events to w as newline-delimited JSON, one Event per
+// line, suitable for bulk-loading into BigQuery, ClickHouse and similar stores
+// for offline analysis.
+//
+// Usage:
+//
+//	f, err := os.Create("errors.ndjson")
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//	return e.ExportNDJSON(f, e.Recent(256))
+func ExportNDJSON(w io.Writer, events []Event) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return Wrap(err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return Wrap(err)
+	}
+	return nil
+}
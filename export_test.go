@@ -0,0 +1,49 @@
+package e
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestExportNDJSON(t *testing.T) {
+	events := []Event{
+		{
+			ID: "a", Code: CodeDatabase, Op: "Foo", Time: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), Fingerprint: "f1",
+			BuildInfo: &BuildInfo{Path: "github.com/acme/billing", Version: "v1.2.3", Revision: "abc123", GoVersion: "go1.18"},
+		},
+		{ID: "b", Op: "Buzz", Time: time.Date(2021, 6, 1, 12, 0, 1, 0, time.UTC), Fingerprint: "f2"},
+	}
+
+	var sb strings.Builder
+	if err := ExportNDJSON(&sb, events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []Event
+	scanner := bufio.NewScanner(strings.NewReader(sb.String()))
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("expected one JSON object per line but got %q: %v", scanner.Text(), err)
+		}
+		got = append(got, event)
+	}
+	if !reflect.DeepEqual(got, events) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, events)
+	}
+
+	if err := ExportNDJSON(failingWriter{}, events); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected write error but got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package e
+
+import (
+	"errors"
+	"sort"
+)
+
+// fieldMap is never modified once attached to an Error; setters copy it.
+type fieldMap map[string]interface{}
+
+// HasFields allows custom error types to be used with utility function
+// ErrorFields().
+type HasFields interface {
+
+	// Fields returns the structured key/value data attached to the error, if any.
+	//
+	// Note: ErrorFields() should be used to merge the fields of the whole chain.
+	Fields() map[string]interface{}
+}
+
+func (e errorImpl) SetField(key string, value interface{}) Error {
+	fields := make(fieldMap, e.fieldCount()+1)
+	e.copyFields(fields)
+	fields[key] = value
+	e.fields = &fields
+	checkSize(e)
+	return e
+}
+
+func (e errorImpl) SetFields(fields map[string]interface{}) Error {
+	merged := make(fieldMap, e.fieldCount()+len(fields))
+	e.copyFields(merged)
+	for key, value := range fields {
+		merged[key] = value
+	}
+	e.fields = &merged
+	checkSize(e)
+	return e
+}
+
+func (e errorImpl) Fields() map[string]interface{} {
+	if e.fields == nil {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(*e.fields))
+	e.copyFields(fields)
+	return fields
+}
+
+func (e errorImpl) fieldCount() int {
+	if e.fields == nil {
+		return 0
+	}
+	return len(*e.fields)
+}
+
+func (e errorImpl) copyFields(dst map[string]interface{}) {
+	if e.fields == nil {
+		return
+	}
+	for key, value := range *e.fields {
+		dst[key] = value
+	}
+}
+
+// ErrorFields returns the fields of every error in the chain which implements
+// HasFields interface, merged into one map. When a key is set more than once,
+// the outermost value wins. Returns nil if there are no fields.
+func ErrorFields(err error) map[string]interface{} {
+	var chain []HasFields
+	for err != nil {
+		if e, ok := err.(HasFields); ok {
+			chain = append(chain, e)
+		}
+		err = errors.Unwrap(err)
+	}
+
+	var fields map[string]interface{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for key, value := range chain[i].Fields() {
+			if fields == nil {
+				fields = make(map[string]interface{})
+			}
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// sortedKeys returns the keys of fields in a stable order for encoders.
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,59 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var errFieldsSentinel = NewError(CodeInternal, "sentinel error")
+
+func TestErrorFields(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want map[string]interface{}
+	}{
+		{
+			name: "no fields returns nil",
+			err:  Foo(),
+			want: nil,
+		},
+		{
+			name: "SetField and SetFields",
+			err: NewError(CodeInternal, "cannot foo").
+				SetField("request_id", "abc").
+				SetFields(map[string]interface{}{"user_id": 1, "retries": 2}),
+			want: map[string]interface{}{"request_id": "abc", "user_id": 1, "retries": 2},
+		},
+		{
+			name: "merges chain and outermost wins",
+			err: Wrap(fmt.Errorf("fmt: %w",
+				NewError(CodeInternal, "cannot foo").SetFields(map[string]interface{}{"id": 1, "table": "users"}),
+			)).SetField("id", 2),
+			want: map[string]interface{}{"id": 2, "table": "users"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorFields(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("setters do not modify the original", func(t *testing.T) {
+		err := NewError(CodeInternal, "cannot foo").SetField("id", 1)
+		err.SetField("id", 2)
+		if got := ErrorFields(err)["id"]; got != 1 {
+			t.Errorf("expected original field to be kept but got %v", got)
+		}
+	})
+	t.Run("errors with fields are comparable", func(t *testing.T) {
+		err := Wrap(errFieldsSentinel).SetField("id", 1)
+		if !errors.Is(err, errFieldsSentinel) {
+			t.Errorf("expected errors.Is to find sentinel")
+		}
+	})
+}
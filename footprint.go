@@ -0,0 +1,7 @@
+//go:build !e_small
+// +build !e_small
+
+package e
+
+// smallFootprint is set by building with the e_small tag. See footprint_small.go.
+const smallFootprint = false
@@ -0,0 +1,15 @@
+//go:build e_small
+// +build e_small
+
+package e
+
+// Building with the e_small tag trims the package for resource-constrained
+// targets such as agents and IoT gateways, while codes, messages, ops and
+// wrapping keep working:
+//   - stacks capture at most smallStackDepth frames, whatever SetMaxStackDepth
+//     or the StackDepth option ask for;
+//   - hooks and job hooks are never called and Recent always returns nothing, so no events
+//     are kept in memory;
+//   - SetMaxErrorSize has no effect, since SizeOf walks values with reflection.
+
+const smallFootprint = true
@@ -0,0 +1,32 @@
+//go:build e_small
+// +build e_small
+
+package e
+
+import "testing"
+
+func TestSmallFootprint(t *testing.T) {
+	var hooked int
+	remove := AddHook(func(Error) { hooked++ })
+	defer remove()
+	removeJob := AddJobHook(func(JobEvent) { hooked++ })
+	defer removeJob()
+
+	SetMaxStackDepth(100)
+	defer SetMaxStackDepth(0)
+	err := NewError(CodeDatabase, "cannot foo")
+	JobRun(Job{Name: "sync"}, func() error { return nil })
+
+	if got := len(ErrorStackFrames(err)); got == 0 || got > smallStackDepth {
+		t.Errorf("expected at most %d frames but got %d", smallStackDepth, got)
+	}
+	if got := ErrorOps(err); len(got) != 1 || got[0] != "TestSmallFootprint" {
+		t.Errorf("expected op of caller but got %v", got)
+	}
+	if hooked != 0 {
+		t.Errorf("expected hooks to be disabled but got %d calls", hooked)
+	}
+	if got := Recent(1); len(got) != 0 {
+		t.Errorf("expected no recent events but got %v", got)
+	}
+}
@@ -0,0 +1,21 @@
+package e
+
+import "testing"
+
+// skipSmallFootprint skips a test of hooks, job hooks, Recent or MaxErrorSize,
+// which are disabled when built with e_small.
+func skipSmallFootprint(t *testing.T) {
+	t.Helper()
+	if smallFootprint {
+		t.Skip("disabled by the e_small build tag")
+	}
+}
+
+// skipNoCapture skips a test of ops or stacks, which are not captured when
+// built with e_noop, tinygo or GOOS=js.
+func skipNoCapture(t *testing.T) {
+	t.Helper()
+	if noCapture {
+		t.Skip("ops and stacks are not captured by this build")
+	}
+}
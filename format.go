@@ -0,0 +1,42 @@
+package e
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkFormat reports a contract violation if formatted, produced by the
+// formatting functions of package fmt for op, contains the markers fmt leaves
+// for formatting mistakes, such as "%!w(MISSING)", "%!d(string=foo)" or
+// "%!(EXTRA int=1)". Such text silently corrupts log pipelines which parse
+// error strings.
+func checkFormat(op, formatted string) {
+	if bad := badFormat(formatted); bad != "" {
+		reportViolation(op, fmt.Sprintf("malformed format %q in %q", bad, formatted))
+	}
+}
+
+// badFormat returns the first formatting mistake marker in s, or "".
+func badFormat(s string) string {
+	for {
+		i := strings.Index(s, "%!")
+		if i < 0 {
+			return ""
+		}
+		s = s[i:]
+
+		// The marker is "%!" and an optional verb followed by "(...)".
+		rest := s[2:]
+		if _, size := utf8.DecodeRuneInString(rest); size > 0 && !strings.HasPrefix(rest, "(") {
+			rest = rest[size:]
+		}
+		if strings.HasPrefix(rest, "(") {
+			if end := strings.IndexByte(rest, ')'); end >= 0 {
+				return s[:len(s)-len(rest)+end+1]
+			}
+			return s
+		}
+		s = s[2:]
+	}
+}
@@ -0,0 +1,66 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBadFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "well formed", s: "cannot find id: 13", want: ""},
+		{name: "missing argument", s: "cannot find: %!w(MISSING)", want: "%!w(MISSING)"},
+		{name: "wrong type", s: "id: %!d(string=abc)", want: "%!d(string=abc)"},
+		{name: "extra argument", s: "id: 1%!(EXTRA int=2)", want: "%!(EXTRA int=2)"},
+		{name: "no verb", s: "100%!(NOVERB)", want: "%!(NOVERB)"},
+		{name: "literal percent bang", s: "done 100%! great", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := badFormat(tt.s); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	skipNoCapture(t)
+	skipSmallFootprint(t)
+
+	var violations []string
+	remove := AddHook(func(err Error) {
+		if ErrorCode(err) == CodeContractViolation {
+			violations = append(violations, err.Error())
+		}
+	})
+	defer remove()
+
+	NewErrorf(CodeDatabase, "id: %d", 13)
+	Wrapf(errors.New("basic error"), "id: %d", 13)
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations but got %v", violations)
+	}
+
+	// Formats are variables so that vet does not catch the mistakes first.
+	idFormat, twoIDsFormat, wrapFormat := "id: %d", "id: %d %d", "cannot %w"
+	NewErrorf(CodeDatabase, idFormat, "abc")
+	Wrapf(errors.New("basic error"), twoIDsFormat, 13)
+	Checkf(false, CodeDatabase, wrapFormat)
+	want := []string{
+		`TestCheckFormat: [contract_violation] malformed format "%!d(string=abc)" in "id: %!d(string=abc)"`,
+		`TestCheckFormat: [contract_violation] malformed format "%!d(MISSING)" in "id: 13 %!d(MISSING)"`,
+		`TestCheckFormat: [contract_violation] malformed format "%!w(MISSING)" in "cannot %!w(MISSING)"`,
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("expected %d violations but got %v", len(want), violations)
+	}
+	for i := range want {
+		if violations[i] != want[i] {
+			t.Errorf("\ngot:  %q\nwant: %q", violations[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package e
+
+import (
+	"errors"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Frame is one function call of a stack.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+
+	// Handoff counts the goroutines the error was passed across before this
+	// frame: 0 for the goroutine which created the error. See
+	// WrapAcrossGoroutine.
+	Handoff int `json:"handoff,omitempty"`
+
+	// InApp reports whether Function belongs to package main or the main
+	// module of the binary rather than a dependency or the standard library,
+	// like the in_app flag error trackers such as Sentry use for grouping.
+	InApp bool `json:"in_app,omitempty"`
+}
+
+// mainModule is the module path of the binary, used to set Frame.InApp.
+var mainModule = func() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+}()
+
+// inApp reports whether function belongs to package main or mainModule.
+func inApp(function string) bool {
+	if strings.HasPrefix(function, "main.") {
+		return true
+	}
+	return mainModule != "" &&
+		(strings.HasPrefix(function, mainModule+".") || strings.HasPrefix(function, mainModule+"/"))
+}
+
+// Symbolizer resolves captured program counters into frames. Custom
+// implementations can use DWARF data for stripped binaries or remap frames
+// through a symbol server, delegating to RuntimeSymbolizer for anything they
+// cannot resolve.
+type Symbolizer interface {
+	Symbolize(pcs []uintptr) []Frame
+}
+
+// SymbolizerFunc adapts a function to a Symbolizer.
+type SymbolizerFunc func(pcs []uintptr) []Frame
+
+func (f SymbolizerFunc) Symbolize(pcs []uintptr) []Frame {
+	return f(pcs)
+}
+
+// RuntimeSymbolizer resolves program counters in-process with
+// runtime.CallersFrames. It is the default Symbolizer.
+var RuntimeSymbolizer Symbolizer = SymbolizerFunc(framesOf)
+
+// SetSymbolizer changes how stacks are resolved into frames by
+// ErrorStackFrames and ErrorStacktrace. nil restores RuntimeSymbolizer. Each
+// stack is resolved once, with the Symbolizer active when it was captured.
+//
+// Usage:
+//
+//	e.SetSymbolizer(e.SymbolizerFunc(func(pcs []uintptr) []e.Frame {
+//		if frames, ok := symbols.Lookup(buildID, pcs); ok {
+//			return frames
+//		}
+//		return e.RuntimeSymbolizer.Symbolize(pcs)
+//	}))
+func SetSymbolizer(symbolizer Symbolizer) {
+	Configure(func(c *Config) {
+		c.Symbolizer = symbolizer
+	})
+}
+
+func (c *Config) symbolizer() Symbolizer {
+	if c.Symbolizer != nil {
+		return c.Symbolizer
+	}
+	return RuntimeSymbolizer
+}
+
+// stack is a captured stack. Only program counters are captured when an Error
+// is created; they are symbolized into frames the first time the stack is
+// requested, since most errors are handled without ever being logged.
+type stack struct {
+	pcs []uintptr
+
+	// extends is the stack of the goroutine which sent the error, if pcs
+	// were captured by the goroutine which received it.
+	extends *stack
+
+	// config is the Config the stack was captured with, whose Symbolizer and
+	// StackTrim resolve it.
+	config *Config
+
+	once   sync.Once
+	frames []Frame
+}
+
+// Frames returns the symbolized frames of s, following the frames of the
+// stack it extends. They are resolved once and shared by every Error which
+// wraps s.
+func (s *stack) Frames() []Frame {
+	if s.empty() {
+		return nil
+	}
+	s.once.Do(func() {
+		c := s.config
+		if c == nil {
+			c = loadConfig()
+		}
+		frames := skipHelperFrames(c.StackTrim.apply(c.symbolizer().Symbolize(s.pcs)))
+		for i := range frames {
+			frames[i].InApp = inApp(frames[i].Function)
+		}
+		if s.extends.empty() {
+			s.frames = frames
+			return
+		}
+
+		// Count hand-offs from the stacks rather than the sent frames, which a
+		// Symbolizer may have left empty.
+		handoff := 0
+		for sender := s.extends; sender != nil; sender = sender.extends {
+			handoff++
+		}
+		sent := s.extends.Frames()
+		s.frames = make([]Frame, 0, len(sent)+len(frames))
+		s.frames = append(s.frames, sent...)
+		for _, f := range frames {
+			f.Handoff = handoff
+			s.frames = append(s.frames, f)
+		}
+	})
+	return s.frames
+}
+
+// skipHelperFrames removes the leading frames of functions marked with Helper,
+// so that stacks start at the same function as the op. Frames are returned
+// unchanged if every frame is a helper.
+func skipHelperFrames(frames []Frame) []Frame {
+	for i, f := range frames {
+		if !isHelper(f.Function) {
+			return frames[i:]
+		}
+	}
+	return frames
+}
+
+func (s *stack) empty() bool {
+	return s == nil || len(s.pcs) == 0 && s.extends.empty()
+}
+
+// withStack captures the program counters of the calling goroutine.
+// frameOffset counts frames like getCallingFunc from the caller of withStack.
+// An empty stack is recorded if c disables stack capture or samples it out, so
+// that wrapping the Error does not capture one either.
+func (e errorImpl) withStack(c *Config, frameOffset int) errorImpl {
+	if c.noStack || !c.sampleStack(e.code) {
+		e.stack = &stack{}
+		return e
+	}
+	e.stack = &stack{pcs: capturePCs(frameOffset+1, c.stackDepth()), config: c}
+	return e
+}
+
+// innermostStack returns the stack of the innermost Error in the chain of err
+// which has one, or the outermost stack extending it with WrapAcrossGoroutine,
+// and whether any error in the chain has a stack, including custom error types
+// implementing HasStacktrace.
+func innermostStack(err error) (s *stack, ok bool) {
+	for err != nil {
+		if e, isImpl := err.(errorImpl); isImpl {
+			if e.stack != nil && e.stack.extends != nil {
+				return e.stack, true
+			}
+			if e.stack != nil {
+				s, ok = e.stack, true
+			}
+		} else if e, isStack := err.(HasStacktrace); isStack && e.Stacktrace() != "" {
+			ok = true
+		}
+		err = errors.Unwrap(err)
+	}
+	return s, ok
+}
+
+// HasStackFrames allows custom error types to be used with utility function
+// ErrorStackFrames().
+type HasStackFrames interface {
+
+	// StackFrames returns the innermost stack as frames, if any.
+	StackFrames() []Frame
+}
+
+func (e errorImpl) StackFrames() []Frame {
+	return e.stack.Frames()
+}
+
+// ErrorStackFrames returns the innermost stack frames of an error which
+// implements HasStackFrames interface, starting with the function which created
+// the error. Otherwise returns nil. Frames of goroutines which received the
+// error through WrapAcrossGoroutine follow, with increasing Frame.Handoff.
+func ErrorStackFrames(err error) []Frame {
+	var stack HasStackFrames
+	for err != nil {
+		if e, ok := err.(errorImpl); ok {
+			if e.stack != nil && e.stack.extends != nil {
+				return e.StackFrames()
+			}
+			if !e.stack.empty() {
+				stack = e
+			}
+		} else if e, ok := err.(HasStackFrames); ok && e.StackFrames() != nil {
+			stack = e
+		}
+		err = errors.Unwrap(err)
+	}
+	if stack == nil {
+		return nil
+	}
+	return stack.StackFrames()
+}
+
+// StackFrames is shorthand for ErrorStackFrames, for integrations such as
+// Sentry or OpenTelemetry which report structured frames.
+//
+// Usage:
+//
+//	for _, f := range e.StackFrames(err) {
+//		span.AddEvent(f.Function, trace.WithAttributes(
+//			attribute.String("file", f.File),
+//			attribute.Int("line", f.Line),
+//		))
+//	}
+func StackFrames(err error) []Frame {
+	return ErrorStackFrames(err)
+}
@@ -0,0 +1,132 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorStackFrames(t *testing.T) {
+	skipNoCapture(t)
+
+	if got := ErrorStackFrames(errors.New("basic error")); got != nil {
+		t.Errorf("expected no frames for non-pkg error but got %v", got)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "NewError starts at caller", err: Foo(), want: "e.Foo"},
+		{name: "Wrap keeps innermost frames", err: Wrap(fmt.Errorf("fmt: %w", Bar())), want: "e.Foo"},
+		{name: "Wrap of non-pkg error starts at caller", err: Buzz(), want: "e.Buzz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := ErrorStackFrames(tt.err)
+			if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, tt.want) {
+				t.Fatalf("expected frames starting at %s but got %v", tt.want, frames)
+			}
+			if frames[0].Line == 0 || !strings.HasSuffix(frames[0].File, "error_test.go") {
+				t.Errorf("unexpected first frame %+v", frames[0])
+			}
+			if want := fmt.Sprintf("%s(...)\n\t%s:%d\n", frames[0].Function, frames[0].File, frames[0].Line); !strings.HasPrefix(ErrorStacktrace(tt.err), want) {
+				t.Errorf("expected stacktrace starting with %q but got %q", want, ErrorStacktrace(tt.err))
+			}
+			if got := StackFrames(tt.err); len(got) != len(frames) {
+				t.Errorf("expected StackFrames to match ErrorStackFrames but got %v", got)
+			}
+		})
+	}
+}
+
+func TestFrameInApp(t *testing.T) {
+	tests := []struct {
+		function string
+		want     bool
+	}{
+		{function: "main.main", want: true},
+		{function: "github.com/kisunji/e.Foo", want: true},
+		{function: "github.com/kisunji/e/etest.(*Recorder).record", want: true},
+		{function: "github.com/kisunji/errgroup.Wait", want: false},
+		{function: "runtime.goexit", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			if got := inApp(tt.function); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+
+	skipNoCapture(t)
+	frames := ErrorStackFrames(Foo())
+	if len(frames) < 2 || !frames[0].InApp || frames[len(frames)-1].InApp {
+		t.Errorf("expected only frames of this module to be in app but got %+v", frames)
+	}
+}
+
+func TestStackFramesResolvedOnce(t *testing.T) {
+	skipNoCapture(t)
+
+	err := Foo()
+	if s := err.(errorImpl).stack; s.frames != nil {
+		t.Fatalf("expected frames to be resolved lazily but got %v", s.frames)
+	}
+
+	frames := ErrorStackFrames(err)
+	if got := ErrorStackFrames(Wrap(err)); len(got) == 0 || &got[0] != &frames[0] {
+		t.Errorf("expected wrapped error to share resolved frames")
+	}
+}
+
+func BenchmarkNewError(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = NewError(CodeDatabase, "cannot foo")
+	}
+}
+
+func TestSetSymbolizer(t *testing.T) {
+	skipNoCapture(t)
+
+	captured := Foo()
+	var calls int
+	SetSymbolizer(SymbolizerFunc(func(pcs []uintptr) []Frame {
+		calls++
+		frames := RuntimeSymbolizer.Symbolize(pcs)
+		frames[0].File = "remapped.go"
+		return frames
+	}))
+	defer SetSymbolizer(nil)
+
+	err := Wrap(Foo())
+	if got := ErrorStackFrames(err); len(got) == 0 || got[0].Function != "github.com/kisunji/e.Foo" || got[0].File != "remapped.go" {
+		t.Errorf("expected remapped frames but got %v", got)
+	}
+	if got := ErrorStacktrace(err); !strings.Contains(got, "\tremapped.go:") {
+		t.Errorf("expected remapped stacktrace but got %q", got)
+	}
+	if got := ErrorStackFrames(captured); len(got) == 0 || got[0].File == "remapped.go" {
+		t.Errorf("expected stack captured before SetSymbolizer to keep its symbolizer but got %v", got)
+	}
+	if calls != 1 {
+		t.Errorf("expected stack to be symbolized once but got %d calls", calls)
+	}
+}
+
+func helperNew() error {
+	Helper()
+	return NewError(CodeDatabase, "cannot foo")
+}
+
+func TestStackFramesSkipHelpers(t *testing.T) {
+	skipNoCapture(t)
+
+	frames := ErrorStackFrames(helperNew())
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, ".TestStackFramesSkipHelpers") {
+		t.Errorf("expected stack to start at caller of helper but got %v", frames)
+	}
+}
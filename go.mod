@@ -1,3 +1,3 @@
 module github.com/kisunji/e
 
-go 1.14
+go 1.18
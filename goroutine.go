@@ -0,0 +1,36 @@
+package e
+
+// WrapAcrossGoroutine wraps err like Wrap and appends the stack of the calling
+// goroutine to the stack of err, so that errors sent over channels, e.g. from a
+// worker pool, show both where they were created and where they were
+// consumed. ErrorStackFrames marks the appended frames with Frame.Handoff. The
+// stack of the calling goroutine is captured like any other, so it is subject
+// to SetStackSampling and SetMaxStackDepth.
+//
+// Usage:
+//
+//	for res := range results {
+//		if res.err != nil {
+//			return e.WrapAcrossGoroutine(res.err)
+//		}
+//	}
+func WrapAcrossGoroutine(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	c := loadConfig()
+	sent, _ := innermostStack(err)
+	wrapped := wrapIn(c, err, 3)
+	if sent.empty() {
+		// wrapIn captured the stack of this goroutine, if any.
+		return wrapped
+	}
+	received := wrapped.withStack(c, 2)
+	if received.stack.empty() {
+		// Stack capture is disabled or sampled out; keep the sent stack alone.
+		return wrapped
+	}
+	received.stack.extends = sent
+	return received
+}
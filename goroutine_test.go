@@ -0,0 +1,93 @@
+package e
+
+import (
+	"strings"
+	"testing"
+)
+
+func receive(errs <-chan error) error {
+	return WrapAcrossGoroutine(<-errs)
+}
+
+func TestWrapAcrossGoroutine(t *testing.T) {
+	skipNoCapture(t)
+
+	if got := WrapAcrossGoroutine(nil); got != nil {
+		t.Fatalf("expected nil but got %v", got)
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- Foo() }()
+	err := receive(errs)
+
+	frames := ErrorStackFrames(Wrap(err))
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, ".Foo") || frames[0].Handoff != 0 {
+		t.Fatalf("expected frames to start where the error was created but got %v", frames)
+	}
+	var received *Frame
+	for i := range frames {
+		if frames[i].Handoff == 1 {
+			received = &frames[i]
+			break
+		}
+	}
+	if received == nil || !strings.HasSuffix(received.Function, ".receive") {
+		t.Fatalf("expected frames of receiving goroutine but got %v", frames)
+	}
+	if got := ErrorStacktrace(err); !strings.Contains(got, "received by goroutine:\n"+received.Function) {
+		t.Errorf("expected stacktrace to show hand-off but got %q", got)
+	}
+
+	errs <- err
+	frames = ErrorStackFrames(receive(errs))
+	if last := frames[len(frames)-1]; last.Handoff != 2 {
+		t.Errorf("expected second hand-off to be counted but got %v", frames)
+	}
+
+	if got := ErrorStacktrace(WrapAcrossGoroutine(NewErrorNoTrace(CodeValidation, "invalid email"))); got != "" {
+		t.Errorf("expected no stack for error without one but got %q", got)
+	}
+}
+
+func TestWrapAcrossGoroutineUnresolvedSender(t *testing.T) {
+	skipNoCapture(t)
+
+	SetSymbolizer(SymbolizerFunc(func(pcs []uintptr) []Frame {
+		frames := RuntimeSymbolizer.Symbolize(pcs)
+		if len(frames) > 0 && strings.HasSuffix(frames[0].Function, ".Foo") {
+			return nil
+		}
+		return frames
+	}))
+	defer SetSymbolizer(nil)
+
+	errs := make(chan error, 1)
+	go func() { errs <- Foo() }()
+	frames := ErrorStackFrames(receive(errs))
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, ".receive") || frames[0].Handoff != 1 {
+		t.Errorf("expected only frames of receiving goroutine but got %v", frames)
+	}
+}
+
+func TestWrapAcrossGoroutineSampled(t *testing.T) {
+	skipNoCapture(t)
+
+	SetStackSampling(2)
+	defer SetStackSampling(0)
+
+	errs := make(chan error, 1)
+	var handoffs int
+	for i := 0; i < 2; i++ {
+		go func() { errs <- NewError(CodePanic, "always sampled") }()
+		frames := ErrorStackFrames(receive(errs))
+		if len(frames) == 0 {
+			t.Fatalf("expected frames of sending goroutine")
+		}
+		if frames[len(frames)-1].Handoff != 0 {
+			handoffs++
+		}
+	}
+	if handoffs != 1 {
+		t.Errorf("expected 1 of 2 receiving stacks to be sampled but got %d", handoffs)
+	}
+}
@@ -0,0 +1,51 @@
+package e
+
+import "context"
+
+// SetGoroutineInfo makes new error stacks record the ID of the goroutine which
+// created them in the KeyGoroutine field, and NewErrorContext and WrapContext
+// record the pprof labels of their context in the KeyProfileLabels field. In
+// concurrency-heavy systems, knowing which worker pool or shard produced an
+// error often matters more than the stack. It is opt-in since reading the
+// goroutine ID costs about a microsecond per error stack.
+//
+// Labels are only available to the Context constructors, after hooks have
+// seen the Error, since Go does not expose the labels of a goroutine without
+// its context.
+//
+// Usage:
+//
+//	e.SetGoroutineInfo(true)
+//
+//	pprof.Do(ctx, pprof.Labels("pool", "ingest", "shard", shard), func(ctx context.Context) {
+//		if err := process(ctx, batch); err != nil {
+//			log.Error(e.WrapContext(ctx, err))
+//		}
+//	})
+func SetGoroutineInfo(enabled bool) {
+	Configure(func(c *Config) {
+		c.GoroutineInfo = enabled
+	})
+}
+
+// withGoroutine records the ID of the calling goroutine on e, if it is known.
+func (e errorImpl) withGoroutine() errorImpl {
+	id := goroutineID()
+	if id == 0 {
+		return e
+	}
+	return e.SetField(KeyGoroutine, id).(errorImpl)
+}
+
+// withProfileLabels records the pprof labels of ctx on e, if c enables
+// GoroutineInfo and ctx has any.
+func (e errorImpl) withProfileLabels(ctx context.Context, c *Config) errorImpl {
+	if !c.GoroutineInfo {
+		return e
+	}
+	labels := profileLabels(ctx)
+	if len(labels) == 0 {
+		return e
+	}
+	return e.SetField(KeyProfileLabels, labels).(errorImpl)
+}
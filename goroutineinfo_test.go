@@ -0,0 +1,45 @@
+package e
+
+import (
+	"context"
+	"errors"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestSetGoroutineInfo(t *testing.T) {
+	skipNoCapture(t)
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("pool", "ingest"))
+
+	if got := ErrorFields(NewErrorContext(ctx, CodeDatabase, "cannot foo")); len(got) != 0 {
+		t.Errorf("expected no fields by default but got %v", got)
+	}
+
+	SetGoroutineInfo(true)
+	defer SetGoroutineInfo(false)
+	goroutine := goroutineID()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantLabels bool
+	}{
+		{name: "NewError", err: Foo()},
+		{name: "NewErrorContext", err: NewErrorContext(ctx, CodeDatabase, "cannot foo"), wantLabels: true},
+		{name: "WrapContext", err: WrapContext(ctx, errors.New("basic error")), wantLabels: true},
+		{name: "WrapContext without labels", err: WrapContext(context.Background(), errors.New("basic error"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := ErrorFields(tt.err)
+			if id, ok := fields[KeyGoroutine].(uint64); !ok || id != goroutine {
+				t.Errorf("expected goroutine %d but got %v", goroutine, fields[KeyGoroutine])
+			}
+			labels, ok := fields[KeyProfileLabels].(map[string]string)
+			if ok != tt.wantLabels || tt.wantLabels && labels["pool"] != "ingest" {
+				t.Errorf("unexpected labels %v", fields[KeyProfileLabels])
+			}
+		})
+	}
+}
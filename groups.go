@@ -0,0 +1,96 @@
+package e
+
+import (
+	"sort"
+	"time"
+)
+
+// EventGroup counts Events sharing a key at one level of the hierarchy built by
+// GroupEvents.
+type EventGroup struct {
+	// Key is the code, fingerprint or op shared by the Events of the group.
+	Key string `json:"key"`
+
+	// Path joins the keys of the group and its ancestors with "/", like the
+	// names of subtests, e.g. "database_error/3f2a9c0e1b4d5a67/GetBar".
+	Path string `json:"path"`
+
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+
+	// Groups subdivides the group: by fingerprint within a code, and by op
+	// within a fingerprint.
+	Groups []EventGroup `json:"groups,omitempty"`
+}
+
+// GroupEvents groups events by code, then by fingerprint, then by op, so that
+// "what broke most" can be read from the top of the result. Groups are sorted
+// by decreasing count, then by key. Events without a code are grouped under an
+// empty key.
+//
+// Usage:
+//
+//	hourAgo := time.Now().Add(-time.Hour)
+//	var lastHour []e.Event
+//	for _, ev := range e.Recent(256) {
+//		if ev.Time.After(hourAgo) {
+//			lastHour = append(lastHour, ev)
+//		}
+//	}
+//	for _, g := range e.GroupEvents(lastHour) {
+//		fmt.Printf("%6d %s (last seen %s)\n", g.Count, g.Key, g.LastSeen)
+//	}
+func GroupEvents(events []Event) []EventGroup {
+	return groupEvents(events, "", []func(Event) string{
+		func(ev Event) string { return ev.Code },
+		func(ev Event) string { return ev.Fingerprint },
+		func(ev Event) string { return ev.Op },
+	})
+}
+
+// groupEvents groups events by the first of keys, then recursively by the
+// rest within each group.
+func groupEvents(events []Event, parent string, keys []func(Event) string) []EventGroup {
+	if len(keys) == 0 || len(events) == 0 {
+		return nil
+	}
+
+	var (
+		groups  []EventGroup
+		members = make(map[string][]Event)
+	)
+	for _, ev := range events {
+		key := keys[0](ev)
+		if _, ok := members[key]; !ok {
+			path := key
+			if parent != "" {
+				path = parent + "/" + key
+			}
+			groups = append(groups, EventGroup{Key: key, Path: path, FirstSeen: ev.Time, LastSeen: ev.Time})
+		}
+		members[key] = append(members[key], ev)
+	}
+
+	for i := range groups {
+		g := &groups[i]
+		for _, ev := range members[g.Key] {
+			g.Count++
+			if ev.Time.Before(g.FirstSeen) {
+				g.FirstSeen = ev.Time
+			}
+			if ev.Time.After(g.LastSeen) {
+				g.LastSeen = ev.Time
+			}
+		}
+		g.Groups = groupEvents(members[g.Key], g.Path, keys[1:])
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
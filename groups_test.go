@@ -0,0 +1,56 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupEvents(t *testing.T) {
+	at := func(sec int) time.Time {
+		return time.Date(2021, 6, 1, 12, 0, sec, 0, time.UTC)
+	}
+	events := []Event{
+		{Code: CodeDatabase, Fingerprint: "f1", Op: "Foo", Time: at(3)},
+		{Code: "", Fingerprint: "f3", Op: "Buzz", Time: at(2)},
+		{Code: CodeDatabase, Fingerprint: "f1", Op: "Foo", Time: at(1)},
+		{Code: CodeDatabase, Fingerprint: "f2", Op: "Bar", Time: at(5)},
+	}
+
+	want := []EventGroup{
+		{
+			Key: CodeDatabase, Path: CodeDatabase, Count: 3, FirstSeen: at(1), LastSeen: at(5),
+			Groups: []EventGroup{
+				{
+					Key: "f1", Path: "database_error/f1", Count: 2, FirstSeen: at(1), LastSeen: at(3),
+					Groups: []EventGroup{
+						{Key: "Foo", Path: "database_error/f1/Foo", Count: 2, FirstSeen: at(1), LastSeen: at(3)},
+					},
+				},
+				{
+					Key: "f2", Path: "database_error/f2", Count: 1, FirstSeen: at(5), LastSeen: at(5),
+					Groups: []EventGroup{
+						{Key: "Bar", Path: "database_error/f2/Bar", Count: 1, FirstSeen: at(5), LastSeen: at(5)},
+					},
+				},
+			},
+		},
+		{
+			Key: "", Path: "", Count: 1, FirstSeen: at(2), LastSeen: at(2),
+			Groups: []EventGroup{
+				{
+					Key: "f3", Path: "f3", Count: 1, FirstSeen: at(2), LastSeen: at(2),
+					Groups: []EventGroup{
+						{Key: "Buzz", Path: "f3/Buzz", Count: 1, FirstSeen: at(2), LastSeen: at(2)},
+					},
+				},
+			},
+		},
+	}
+	if got := GroupEvents(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+	if got := GroupEvents(nil); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+}
@@ -0,0 +1,117 @@
+package e
+
+import (
+	"context"
+	"time"
+)
+
+// Response is the client-facing description of an error produced by
+// Handler.Handle, shared by HTTP, gRPC and CLI adapters.
+type Response struct {
+	// HTTPStatus and GRPCCode are the statuses registered for the code of
+	// the error, see HTTPStatus and GRPCCode.
+	HTTPStatus int    `json:"-"`
+	GRPCCode   uint32 `json:"-"`
+
+	Code       string        `json:"code,omitempty"`
+	Message    string        `json:"message,omitempty"`
+	Suggestion string        `json:"suggestion,omitempty"`
+	HelpURL    string        `json:"help_url,omitempty"`
+	Details    interface{}   `json:"details,omitempty"`
+	ErrorID    string        `json:"error_id,omitempty"`
+	Retryable  bool          `json:"retryable,omitempty"`
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+}
+
+// Handler defines how a service presents errors at its boundaries, so that
+// every adapter behaves the same way. Every field is optional.
+//
+// Usage:
+//
+//	var boundary = &e.Handler{
+//		Sanitize: hideInternalErrors,
+//		Localize: func(ctx context.Context, code e.Code, message string) string {
+//			return i18n.Translate(ctx, code, message)
+//		},
+//		Log: func(ctx context.Context, err error) {
+//			logger.Errorw(err.Error(), e.KeysAndValues(err)...)
+//		},
+//		Count: func(ctx context.Context, code e.Code) {
+//			errorsTotal.WithLabelValues(code).Inc()
+//		},
+//		DefaultMessage: "Something went wrong. Please try again.",
+//	}
+//
+//	func writeError(w http.ResponseWriter, r *http.Request, err error) {
+//		resp := boundary.Handle(r.Context(), err)
+//		w.WriteHeader(resp.HTTPStatus)
+//		json.NewEncoder(w).Encode(resp)
+//	}
+type Handler struct {
+	// Sanitize may replace the error before it is described, e.g. to hide
+	// the details of unexpected errors. Log receives the original error.
+	Sanitize func(err error) error
+
+	// MapCode maps the code of the error to the code returned to clients,
+	// e.g. to hide internal codes. Statuses are resolved before mapping.
+	MapCode func(code Code) Code
+
+	// Localize translates the message of the error for the client of ctx.
+	Localize func(ctx context.Context, code Code, message string) string
+
+	// Log is called with every handled error.
+	Log func(ctx context.Context, err error)
+
+	// Count is called with the code returned to clients of every handled
+	// error, e.g. to increment a metric.
+	Count func(ctx context.Context, code Code)
+
+	// DefaultMessage is used for errors with no message and no message
+	// registered for their code.
+	DefaultMessage string
+}
+
+// Handle logs and counts err and returns its Response. A nil err yields a
+// Response with HTTP status 200 and gRPC code OK, and is neither logged nor
+// counted.
+func (h *Handler) Handle(ctx context.Context, err error) Response {
+	if err == nil {
+		return Response{HTTPStatus: HTTPStatus(nil)}
+	}
+	if h.Log != nil {
+		h.Log(ctx, err)
+	}
+
+	if h.Sanitize != nil {
+		if sanitized := h.Sanitize(err); sanitized != nil {
+			err = sanitized
+		}
+	}
+
+	d, _ := Describe(rawCode(err))
+	resp := Response{
+		HTTPStatus: HTTPStatus(err),
+		GRPCCode:   GRPCCode(err),
+		Code:       string(ErrorCode(err)),
+		Message:    ErrorMessageOr(err, d.Message),
+		Suggestion: ErrorSuggestion(err),
+		HelpURL:    ErrorHelpURL(err),
+		Details:    ErrorDetails(err),
+		ErrorID:    ErrorID(err),
+		Retryable:  IsRetryable(err),
+		RetryAfter: ErrorRetryAfter(err),
+	}
+	if resp.Message == "" {
+		resp.Message = h.DefaultMessage
+	}
+	if h.MapCode != nil {
+		resp.Code = string(h.MapCode(Code(resp.Code)))
+	}
+	if h.Localize != nil {
+		resp.Message = h.Localize(ctx, Code(resp.Code), resp.Message)
+	}
+	if h.Count != nil {
+		h.Count(ctx, Code(resp.Code))
+	}
+	return resp
+}
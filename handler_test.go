@@ -0,0 +1,92 @@
+package e
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	RegisterCode("test_handler_missing",
+		WithHTTPStatus(404),
+		WithGRPCCode(5),
+		WithMessage("The resource does not exist."),
+	)
+
+	var logged []error
+	var counted []Code
+	h := &Handler{
+		Sanitize: func(err error) error {
+			if IsCode(err, CodeDatabase) {
+				return NewError(CodeInternal, "sanitized").SetID(ErrorID(err))
+			}
+			return nil
+		},
+		MapCode: func(code Code) Code {
+			if code == "test_handler_missing" {
+				return "not_found"
+			}
+			return code
+		},
+		Localize: func(ctx context.Context, code Code, message string) string {
+			return "fr: " + message
+		},
+		Log:            func(ctx context.Context, err error) { logged = append(logged, err) },
+		Count:          func(ctx context.Context, code Code) { counted = append(counted, code) },
+		DefaultMessage: "Something went wrong.",
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want Response
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: Response{HTTPStatus: 200},
+		},
+		{
+			name: "registered code",
+			err:  NewError("test_handler_missing", "no bar").SetID("abc").SetDetails("bar"),
+			want: Response{
+				HTTPStatus: 404,
+				GRPCCode:   5,
+				Code:       "not_found",
+				Message:    "fr: The resource does not exist.",
+				Details:    "bar",
+				ErrorID:    "abc",
+			},
+		},
+		{
+			name: "sanitized error",
+			err:  Wrap(Foo()).SetMessage("Table bars is locked.").SetID("def"),
+			want: Response{
+				HTTPStatus: 500,
+				GRPCCode:   2,
+				Code:       CodeInternal,
+				Message:    "fr: Something went wrong.",
+				ErrorID:    "def",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.Handle(context.Background(), tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+
+	if len(logged) != 2 || !IsCode(logged[1], CodeDatabase) {
+		t.Errorf("expected original errors to be logged but got %v", logged)
+	}
+	if want := []Code{"not_found", CodeInternal}; !reflect.DeepEqual(counted, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", counted, want)
+	}
+
+	var zero Handler
+	if got := zero.Handle(context.Background(), Foo()); got.Code != CodeDatabase || got.HTTPStatus != 500 {
+		t.Errorf("expected zero Handler to describe error but got %+v", got)
+	}
+}
@@ -0,0 +1,72 @@
+package e
+
+import "errors"
+
+// hook wraps a function so it can be identified for removal.
+type hook struct {
+	fn func(Error)
+}
+
+// AddHook registers fn to be called synchronously with every Error which starts
+// a new error stack (NewError, NewErrorf, Check, Derive, or Wrap of an error
+// without a stacktrace). fn sees the Error as constructed, before any SetX
+// calls, and is not called for quiet codes. Errors caused by context.Canceled
+// are handled according to SetCancellationPolicy. The returned function removes the
+// hook.
+//
+// Hooks must be fast and safe for concurrent use since they run on the calling
+// goroutine of every constructor. Hooks are never called in builds with the
+// e_small tag.
+func AddHook(fn func(Error)) (remove func()) {
+	h := &hook{fn: fn}
+	updateConfig(func(c *Config) error {
+		c.hooks = append(c.hooks[:len(c.hooks):len(c.hooks)], h)
+		return nil
+	})
+
+	return func() {
+		updateConfig(func(c *Config) error {
+			for i, other := range c.hooks {
+				if other == h {
+					hooks := make([]*hook, 0, len(c.hooks)-1)
+					hooks = append(hooks, c.hooks[:i]...)
+					c.hooks = append(hooks, c.hooks[i+1:]...)
+					break
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// notifyHooks calls every hook registered in c with a newly created Error,
+// subject to the CancellationPolicy of c.
+func (c *Config) notifyHooks(e errorImpl) {
+	if smallFootprint {
+		return
+	}
+	e, ok := c.applyCancellationPolicy(e)
+	if !ok {
+		return
+	}
+	for _, h := range c.hooks {
+		h.fn(e)
+	}
+}
+
+// reportViolation builds an Error with code CodeContractViolation for misuse
+// detected in op. In dev mode it panics with the Error, otherwise it is passed
+// to hooks.
+func reportViolation(op, cause string) {
+	c := loadConfig()
+	v := errorImpl{
+		op:   op,
+		code: CodeContractViolation,
+		err:  errors.New(cause),
+	}.withStack(c, 1).withOrigin(c)
+	recordOrigin(v)
+	if c.DevMode {
+		panic(v)
+	}
+	c.notifyHooks(v)
+}
@@ -0,0 +1,33 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddHook(t *testing.T) {
+	skipNoCapture(t)
+	skipSmallFootprint(t)
+
+	var created []Error
+	remove := AddHook(func(err Error) {
+		created = append(created, err)
+	})
+
+	RegisterQuietCodes("not_exists")
+	defer UnregisterQuietCodes("not_exists")
+
+	Foo()                               // new stack
+	Wrap(errors.New("basic error"))     // new stack
+	Wrap(NewError("not_exists", "bar")) // quiet
+	Wrap(Foo())                         // stack of Foo is reused
+	remove()
+	Foo()
+
+	if len(created) != 3 {
+		t.Fatalf("expected 3 errors but got %d: %v", len(created), created)
+	}
+	if got, want := created[0].Error(), "Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
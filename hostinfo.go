@@ -0,0 +1,76 @@
+package e
+
+import "os"
+
+// HostInfo describes the process which created an Error, so error events
+// arriving at central sinks are self-describing.
+type HostInfo struct {
+	Hostname string `json:"hostname,omitempty"`
+	Pod      string `json:"pod,omitempty"`
+	Region   string `json:"region,omitempty"`
+	PID      int    `json:"pid"`
+}
+
+// ReadHostInfo returns the HostInfo of the current process. Pod is read from
+// the POD_NAME environment variable and Region from REGION or AWS_REGION.
+// Fields which cannot be determined are left empty.
+func ReadHostInfo() *HostInfo {
+	hostname, _ := os.Hostname()
+	region := os.Getenv("REGION")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	return &HostInfo{
+		Hostname: hostname,
+		Pod:      os.Getenv("POD_NAME"),
+		Region:   region,
+		PID:      os.Getpid(),
+	}
+}
+
+// SetHostInfo stamps every Error which starts a new error stack with the
+// non-empty fields of info, under the KeyHostname, KeyPod, KeyRegion and KeyPID
+// keys, so they are picked up by ErrorFields, KeysAndValues and the logging
+// adapters. info is read once and cached, so it should be built at init.
+// Stamping is disabled by default and can be disabled again with nil.
+//
+// Usage:
+//
+//	func main() {
+//		e.SetHostInfo(e.ReadHostInfo())
+//		...
+//	}
+func SetHostInfo(info *HostInfo) {
+	Configure(func(c *Config) {
+		c.HostInfo = info
+	})
+}
+
+// fields returns the non-empty fields of h keyed like SetHostInfo stamps them.
+func (h *HostInfo) fields() map[string]interface{} {
+	fields := make(map[string]interface{}, 4)
+	if h.Hostname != "" {
+		fields[KeyHostname] = h.Hostname
+	}
+	if h.Pod != "" {
+		fields[KeyPod] = h.Pod
+	}
+	if h.Region != "" {
+		fields[KeyRegion] = h.Region
+	}
+	if h.PID != 0 {
+		fields[KeyPID] = h.PID
+	}
+	return fields
+}
+
+// withHostInfo records the fields of info on e.
+func (e errorImpl) withHostInfo(info *HostInfo) errorImpl {
+	fields := make(fieldMap, e.fieldCount()+4)
+	e.copyFields(fields)
+	for key, value := range info.fields() {
+		fields[key] = value
+	}
+	e.fields = &fields
+	return e
+}
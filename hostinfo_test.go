@@ -0,0 +1,39 @@
+package e
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSetHostInfo(t *testing.T) {
+	if got := ErrorFields(NewError(CodeInternal, "unstamped")); got != nil {
+		t.Errorf("expected no host fields by default but got %v", got)
+	}
+
+	info := ReadHostInfo()
+	if info.PID != os.Getpid() {
+		t.Errorf("expected pid %d but got %d", os.Getpid(), info.PID)
+	}
+
+	SetHostInfo(&HostInfo{Hostname: "web-1", Region: "eu-west-1", PID: 42})
+	defer SetHostInfo(nil)
+
+	err := Wrap(Foo()).SetField("user", "bob")
+	want := map[string]interface{}{
+		KeyHostname: "web-1",
+		KeyRegion:   "eu-west-1",
+		KeyPID:      42,
+		"user":      "bob",
+	}
+	if got := ErrorFields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+
+	kv := KeysAndValues(err)
+	if got := kv[len(kv)-8:]; !reflect.DeepEqual(got, []interface{}{
+		KeyHostname, "web-1", KeyPID, 42, KeyRegion, "eu-west-1", "user", "bob",
+	}) {
+		t.Errorf("unexpected keys and values %v", got)
+	}
+}
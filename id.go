@@ -0,0 +1,67 @@
+package e
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+// SetIDGenerator replaces the function which generates the correlation ID of
+// every new error stack, e.g. to plug in UUIDv7 or KSUID, or to make IDs
+// deterministic under test. A generator returning "" disables IDs. nil restores
+// the default, which returns 12 random hex characters.
+func SetIDGenerator(generate func() string) {
+	Configure(func(c *Config) {
+		c.IDGenerator = generate
+	})
+}
+
+// newID returns a correlation ID from the configured generator.
+func (c *Config) newID() string {
+	if c.IDGenerator != nil {
+		return c.IDGenerator()
+	}
+	return randomID()
+}
+
+func randomID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// HasErrorID allows custom error types to be used with utility function
+// ErrorID().
+type HasErrorID interface {
+
+	// ID returns a short correlation ID for this occurrence of the error, if any.
+	//
+	// Note: ErrorID() should be used to retrieve the topmost ID().
+	ID() string
+}
+
+func (e errorImpl) SetID(id string) Error {
+	e.id = id
+	return e
+}
+
+func (e errorImpl) ID() string {
+	return e.id
+}
+
+// ErrorID returns the first unwrapped ID of an error which implements HasErrorID
+// interface. Otherwise returns an empty string.
+//
+// Every new error stack is given an ID which is kept when it is wrapped, so it
+// can be shown to users ("error ID 3f9a1c0b7d2e") and grepped for in logs.
+func ErrorID(err error) string {
+	for err != nil {
+		if e, ok := err.(HasErrorID); ok && e.ID() != "" {
+			return e.ID()
+		}
+		err = errors.Unwrap(err)
+	}
+	return ""
+}
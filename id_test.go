@@ -0,0 +1,35 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorID(t *testing.T) {
+	t.Run("new stacks get random IDs", func(t *testing.T) {
+		id1, id2 := ErrorID(Foo()), ErrorID(Foo())
+		if len(id1) != 12 || id1 == id2 {
+			t.Errorf("expected distinct 12 character IDs but got %q and %q", id1, id2)
+		}
+	})
+	t.Run("ID is kept when wrapping", func(t *testing.T) {
+		err := Foo()
+		wrapped := Wrap(fmt.Errorf("fmt: %w", Wrap(err)))
+		if got, want := ErrorID(wrapped), ErrorID(err); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("SetID overrides", func(t *testing.T) {
+		if got := ErrorID(Wrap(Foo()).SetID("abc")); got != "abc" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "abc")
+		}
+	})
+	t.Run("pluggable generator", func(t *testing.T) {
+		SetIDGenerator(func() string { return "fixed" })
+		defer SetIDGenerator(nil)
+		if got := ErrorID(Wrap(errors.New("basic error"))); got != "fixed" {
+			t.Errorf("\ngot:  %q\nwant: %q", got, "fixed")
+		}
+	})
+}
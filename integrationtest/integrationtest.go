@@ -0,0 +1,206 @@
+// Package integrationtest provides a harness for adapters which carry errors
+// created with package e across the wire, such as HTTP middleware and custom
+// encoders. It verifies that the code, message, details and error ID of every
+// error survive the round trip from a fake server to its client.
+package integrationtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+// UpdateEnv is the environment variable which makes RunGolden rewrite its
+// fixtures with the bodies received, e.g. UPDATE_GOLDEN=1 go test ./...
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// Adapter is an integration under test. Encode writes err as the response of
+// a fake HTTP server, and Decode converts the response received by the client
+// back into an error.
+type Adapter struct {
+	Encode func(w http.ResponseWriter, err error)
+	Decode func(resp *http.Response) error
+}
+
+// Case is an error sent across the wire by Run.
+type Case struct {
+	Name string
+	Err  error
+}
+
+// Cases returns the errors sent by Run: codes and messages, details, wrapped
+// errors and errors from other packages made fully formed with Ensure. Every
+// case has a fixed error ID so that wire bodies are reproducible.
+func Cases() []Case {
+	notFound := e.NewError("not_exists", "user 42 not found").
+		SetMessage("The user does not exist.").
+		SetID("01-not-found")
+	return []Case{
+		{
+			Name: "code_and_message",
+			Err:  notFound,
+		},
+		{
+			Name: "details",
+			Err: e.NewError(e.CodeValidation, "invalid request").
+				SetMessage("The request is invalid.").
+				SetDetails(map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{"field": "email", "reason": "must not be empty"},
+					},
+				}).
+				SetID("02-details"),
+		},
+		{
+			Name: "wrapped",
+			Err:  e.Wrap(fmt.Errorf("get user: %w", notFound)),
+		},
+		{
+			Name: "foreign",
+			Err:  e.Ensure(errors.New("connection reset")).SetID("04-foreign"),
+		},
+	}
+}
+
+// Run sends every case of Cases through a, and fails t if the error decoded
+// by the client does not have the same ErrorCode, ErrorMessage, ErrorDetails
+// and ErrorID as the error sent. Details are compared by their JSON encoding,
+// since decoders rarely restore the original types.
+//
+// Usage:
+//
+//	func TestAdapter(t *testing.T) {
+//		integrationtest.Run(t, integrationtest.Adapter{
+//			Encode: middleware.WriteError,
+//			Decode: client.ReadError,
+//		})
+//	}
+func Run(t *testing.T, a Adapter) {
+	t.Helper()
+	run(t, a, "")
+}
+
+// RunGolden is Run which also compares the body of every response with the
+// fixture dir/<case>.golden, so that changes to the wire format are caught in
+// review. Fixtures are rewritten when the UpdateEnv environment variable is
+// set.
+func RunGolden(t *testing.T, a Adapter, dir string) {
+	t.Helper()
+	run(t, a, dir)
+}
+
+func run(t *testing.T, a Adapter, dir string) {
+	cases := Cases()
+	byPath := make(map[string]error, len(cases))
+	for _, c := range cases {
+		byPath["/"+c.Name] = c.Err
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.Encode(w, byPath[r.URL.Path])
+	}))
+	defer srv.Close()
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			resp, err := srv.Client().Get(srv.URL + "/" + c.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dir != "" {
+				compareGolden(t, filepath.Join(dir, c.Name+".golden"), body)
+			}
+
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			received := a.Decode(resp)
+			if received == nil {
+				t.Fatalf("expected an error but Decode returned nil for body %s", body)
+			}
+			compareErrors(t, c.Err, received)
+		})
+	}
+}
+
+func compareErrors(t testing.TB, sent, received error) {
+	t.Helper()
+	if got, want := e.ErrorCode(received), e.ErrorCode(sent); got != want {
+		t.Errorf("expected code %q but got %q", want, got)
+	}
+	if got, want := e.ErrorMessage(received), e.ErrorMessage(sent); got != want {
+		t.Errorf("expected message %q but got %q", want, got)
+	}
+	if got, want := e.ErrorID(received), e.ErrorID(sent); got != want {
+		t.Errorf("expected error ID %q but got %q", want, got)
+	}
+	got, err := json.Marshal(e.ErrorDetails(received))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := json.Marshal(e.ErrorDetails(sent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("expected details %s but got %s", want, got)
+	}
+}
+
+func compareGolden(t *testing.T, path string, body []byte) {
+	t.Helper()
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.WriteFile(path, body, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (set %s=1 to create it)", err, UpdateEnv)
+	}
+	if !bytes.Equal(body, want) {
+		t.Errorf("body does not match %s:\ngot:  %s\nwant: %s", path, body, want)
+	}
+}
+
+// JSONAdapter is the reference Adapter. It writes the e.Response produced by
+// h as JSON with its HTTPStatus, and decodes it into an Error with the same
+// code, message, details and error ID.
+func JSONAdapter(h *e.Handler) Adapter {
+	return Adapter{
+		Encode: func(w http.ResponseWriter, err error) {
+			resp := h.Handle(context.Background(), err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(resp.HTTPStatus)
+			_ = json.NewEncoder(w).Encode(resp)
+		},
+		Decode: func(resp *http.Response) error {
+			if resp.StatusCode < http.StatusBadRequest {
+				return nil
+			}
+			var decoded e.Response
+			if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+				return e.Wrap(err)
+			}
+			cause := fmt.Sprintf("status %d", resp.StatusCode)
+			return e.NewError(e.Code(decoded.Code), cause).
+				SetMessage(decoded.Message).
+				SetDetails(decoded.Details).
+				SetID(decoded.ErrorID)
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package integrationtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kisunji/e"
+)
+
+func TestJSONAdapter(t *testing.T) {
+	RunGolden(t, JSONAdapter(&e.Handler{}), "testdata")
+}
+
+// recorder is a testing.TB which records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func Test_compareErrors(t *testing.T) {
+	sent := Cases()[1].Err
+	tests := []struct {
+		name     string
+		received error
+		want     int
+	}{
+		{name: "same", received: e.Wrap(sent), want: 0},
+		{name: "message lost", received: e.Wrap(sent).SetMessage(" "), want: 1},
+		{name: "everything lost", received: fmt.Errorf("status 400"), want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			compareErrors(r, sent, tt.received)
+			if len(r.failures) != tt.want {
+				t.Errorf("expected %d failures but got %q", tt.want, r.failures)
+			}
+		})
+	}
+}
@@ -24,10 +24,44 @@ type ClientFacing interface {
 
 // ErrorCode returns the first unwrapped Code of an error which implements
 // ClientFacing interface. Otherwise returns an empty string.
-func ErrorCode(err error) string {
+//
+// If a namespace was set with SetCodeNamespace, the code is returned as
+// "namespace.code".
+func ErrorCode(err error) Code {
+	code := rawCode(err)
+	if ns := getCodeNamespace(); ns != "" && code != "" {
+		return Code(ns) + "." + code
+	}
+	return code
+}
+
+// ErrorRootCode returns the last unwrapped Code of an error which implements
+// ClientFacing interface, i.e. the code the error was originally classified
+// with before any SetCode overrides. Otherwise returns an empty string.
+// Namespaces are applied like ErrorCode.
+//
+// Usage:
+//
+//	errorsTotal.WithLabelValues(e.ErrorRootCode(err)).Inc()
+func ErrorRootCode(err error) Code {
+	var code Code
 	for err != nil {
 		if e, ok := err.(ClientFacing); ok && e.ClientCode() != "" {
-			return e.ClientCode()
+			code = Code(e.ClientCode())
+		}
+		err = errors.Unwrap(err)
+	}
+	if ns := getCodeNamespace(); ns != "" && code != "" {
+		return Code(ns) + "." + code
+	}
+	return code
+}
+
+// rawCode returns the first unwrapped Code without any namespace applied.
+func rawCode(err error) Code {
+	for err != nil {
+		if e, ok := err.(ClientFacing); ok && e.ClientCode() != "" {
+			return Code(e.ClientCode())
 		}
 		err = errors.Unwrap(err)
 	}
@@ -46,6 +80,81 @@ func ErrorMessage(err error) string {
 	return ""
 }
 
+// ErrorCodeOr returns ErrorCode(err), or fallback if it is empty.
+//
+// Usage:
+//
+//	writeJSON(w, apiError{
+//		Code:    e.ErrorCodeOr(err, "unexpected_error"),
+//		Message: e.ErrorMessageOr(err, "Something went wrong. Please try again."),
+//	})
+func ErrorCodeOr(err error, fallback Code) Code {
+	if code := ErrorCode(err); code != "" {
+		return code
+	}
+	return fallback
+}
+
+// ErrorMessageOr returns ErrorMessage(err), or fallback if it is empty.
+func ErrorMessageOr(err error, fallback string) string {
+	if msg := ErrorMessage(err); msg != "" {
+		return msg
+	}
+	return fallback
+}
+
+// HasClientDetails extends ClientFacing for custom error types which carry
+// structured details for the client, to be used with utility function
+// ErrorDetails().
+type HasClientDetails interface {
+
+	// ClientDetails returns structured details (if any) to be returned to a
+	// client alongside the code and message, such as per-field errors.
+	//
+	// Note: ErrorDetails() should be used to retrieve the topmost ClientDetails().
+	ClientDetails() interface{}
+}
+
+// ErrorDetails returns the first unwrapped non-nil Details of an error which
+// implements HasClientDetails interface. Otherwise returns nil.
+func ErrorDetails(err error) interface{} {
+	for err != nil {
+		if e, ok := err.(HasClientDetails); ok && e.ClientDetails() != nil {
+			return e.ClientDetails()
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
+
+// HasClientSuggestion extends ClientFacing for custom error types which tell
+// the client what to do next, to be used with utility function ErrorSuggestion().
+type HasClientSuggestion interface {
+
+	// ClientSuggestion returns a user-friendly remediation (if any), such as
+	// "Check the spelling of the email address and try again".
+	//
+	// Note: ErrorSuggestion() should be used to retrieve the topmost ClientSuggestion().
+	ClientSuggestion() string
+}
+
+// ErrorSuggestion returns the first unwrapped Suggestion of an error which
+// implements HasClientSuggestion interface. Otherwise returns an empty string.
+func ErrorSuggestion(err error) string {
+	for err != nil {
+		if e, ok := err.(HasClientSuggestion); ok && e.ClientSuggestion() != "" {
+			return e.ClientSuggestion()
+		}
+		err = errors.Unwrap(err)
+	}
+	return ""
+}
+
+// detailsBox holds details behind a pointer so errorImpl stays comparable.
+type detailsBox struct {
+	v interface{}
+}
+
 // HasStacktrace allows custom error types to be used with utility function
 // ErrorStacktrace().
 type HasStacktrace interface {
@@ -56,13 +165,85 @@ type HasStacktrace interface {
 
 // ErrorStacktrace returns the innermost Stack of an error which implements
 // HasStacktrace interface. Otherwise returns an empty string.
+// Stacks of Errors are formatted from their program counters only when
+// requested, and only the innermost one is formatted. Stacks of goroutines
+// which received the error through WrapAcrossGoroutine are appended.
 func ErrorStacktrace(err error) string {
-	var stack string
+	var stack HasStacktrace
 	for err != nil {
-		if e, ok := err.(HasStacktrace); ok && e.Stacktrace() != "" {
-			stack = e.Stacktrace()
+		if e, ok := err.(errorImpl); ok {
+			if e.stack != nil && e.stack.extends != nil {
+				return e.Stacktrace()
+			}
+			if !e.stack.empty() {
+				stack = e
+			}
+		} else if e, ok := err.(HasStacktrace); ok && e.Stacktrace() != "" {
+			stack = e
 		}
 		err = errors.Unwrap(err)
 	}
-	return stack
+	if stack == nil {
+		return ""
+	}
+	return stack.Stacktrace()
+}
+
+// StackExportable reports whether the stacktrace of err may leave the process,
+// i.e. no Error in the chain was marked with SetNoStackExport. Encoders and
+// sinks must check it before including ErrorStacktrace.
+func StackExportable(err error) bool {
+	for err != nil {
+		if e, ok := err.(errorImpl); ok && e.noStackExport {
+			return false
+		}
+		err = errors.Unwrap(err)
+	}
+	return true
+}
+
+// HasGroupKey allows custom error types to be used with utility function
+// ErrorGroupKey().
+type HasGroupKey interface {
+
+	// GroupKey returns a key which overrides how the error is grouped with
+	// other occurrences, if any.
+	//
+	// Note: ErrorGroupKey() should be used to retrieve the topmost GroupKey().
+	GroupKey() string
+}
+
+// ErrorGroupKey returns the first unwrapped GroupKey of an error which implements
+// HasGroupKey interface. Otherwise returns an empty string.
+func ErrorGroupKey(err error) string {
+	for err != nil {
+		if e, ok := err.(HasGroupKey); ok && e.GroupKey() != "" {
+			return e.GroupKey()
+		}
+		err = errors.Unwrap(err)
+	}
+	return ""
+}
+
+// HasHelpURL allows custom error types to be used with utility function
+// ErrorHelpURL().
+type HasHelpURL interface {
+
+	// HelpURL returns a link to a runbook or documentation page, if any.
+	//
+	// Note: ErrorHelpURL() should be used to retrieve the topmost HelpURL().
+	HelpURL() string
+}
+
+// ErrorHelpURL returns the first unwrapped HelpURL of an error which implements
+// HasHelpURL interface. Otherwise returns the HelpURL registered for the code of
+// err, if any.
+func ErrorHelpURL(err error) string {
+	for inner := err; inner != nil; inner = errors.Unwrap(inner) {
+		if e, ok := inner.(HasHelpURL); ok && e.HelpURL() != "" {
+			return e.HelpURL()
+		}
+	}
+	d, _ := Describe(rawCode(err))
+	return d.HelpURL
 }
@@ -0,0 +1,126 @@
+package e
+
+import "time"
+
+// Job describes a run of a recurring job for JobRun.
+type Job struct {
+	// Name identifies the job, e.g. "nightly_invoices".
+	Name string
+
+	// RunID identifies this run. A new ID is generated like ErrorID if empty.
+	RunID string
+
+	// Schedule describes when the job runs, e.g. a cron expression.
+	Schedule string
+}
+
+// JobPhase is the stage of a job run reported to job hooks.
+type JobPhase int
+
+const (
+	// JobStarted is reported before the job function is called.
+	JobStarted JobPhase = iota
+
+	// JobFinished is reported after the job function returns.
+	JobFinished
+)
+
+func (p JobPhase) String() string {
+	if p == JobStarted {
+		return "started"
+	}
+	return "finished"
+}
+
+// JobEvent is passed to job hooks by JobRun.
+type JobEvent struct {
+	Job   Job
+	Phase JobPhase
+	Time  time.Time
+
+	// Duration and Err are set when Phase is JobFinished. Err is the error
+	// returned by JobRun.
+	Duration time.Duration
+	Err      error
+}
+
+// jobHook wraps a function so it can be identified for removal.
+type jobHook struct {
+	fn func(JobEvent)
+}
+
+// AddJobHook registers fn to be called synchronously when JobRun starts and
+// finishes a job, e.g. to record run history or emit metrics. The returned
+// function removes the hook. Job hooks, like hooks, are never called in builds
+// with the e_small tag.
+func AddJobHook(fn func(JobEvent)) (remove func()) {
+	h := &jobHook{fn: fn}
+	updateConfig(func(c *Config) error {
+		c.jobHooks = append(c.jobHooks[:len(c.jobHooks):len(c.jobHooks)], h)
+		return nil
+	})
+
+	return func() {
+		updateConfig(func(c *Config) error {
+			for i, other := range c.jobHooks {
+				if other == h {
+					hooks := make([]*jobHook, 0, len(c.jobHooks)-1)
+					hooks = append(hooks, c.jobHooks[:i]...)
+					c.jobHooks = append(hooks, c.jobHooks[i+1:]...)
+					break
+				}
+			}
+			return nil
+		})
+	}
+}
+
+func notifyJobHooks(ev JobEvent) {
+	if smallFootprint {
+		return
+	}
+	for _, h := range loadConfig().jobHooks {
+		h.fn(ev)
+	}
+}
+
+// JobRun calls fn as a run of job, reporting its start and finish to job hooks.
+// An error returned by fn is wrapped like Wrap with the KeyJobName, KeyJobRunID
+// and KeyJobSchedule fields, so failures of every cron or worker framework are
+// reported the same way.
+//
+// Usage:
+//
+//	c.AddFunc("0 2 * * *", func() {
+//		err := e.JobRun(e.Job{Name: "nightly_invoices", Schedule: "0 2 * * *"}, sendInvoices)
+//		if err != nil {
+//			log.Error(err, "job failed", e.KeysAndValues(err)...)
+//		}
+//	})
+func JobRun(job Job, fn func() error) error {
+	if job.RunID == "" {
+		job.RunID = loadConfig().newID()
+	}
+
+	start := now()
+	notifyJobHooks(JobEvent{Job: job, Phase: JobStarted, Time: start})
+
+	var wrapped error
+	if err := fn(); err != nil {
+		wrapped = wrap(err, 3).SetFields(map[string]interface{}{
+			KeyJobName:     job.Name,
+			KeyJobRunID:    job.RunID,
+			KeyJobSchedule: job.Schedule,
+		})
+	}
+
+	end := now()
+	notifyJobHooks(JobEvent{
+		Job:      job,
+		Phase:    JobFinished,
+		Time:     end,
+		Duration: end.Sub(start),
+		Err:      wrapped,
+	})
+	return wrapped
+}
@@ -0,0 +1,58 @@
+package e
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJobRun(t *testing.T) {
+	skipNoCapture(t)
+	skipSmallFootprint(t)
+
+	start := time.Date(2021, 6, 1, 2, 0, 0, 0, time.UTC)
+	clock := start
+	SetClock(func() time.Time { return clock })
+	defer SetClock(nil)
+
+	var events []JobEvent
+	remove := AddJobHook(func(ev JobEvent) {
+		events = append(events, ev)
+	})
+	defer remove()
+
+	job := Job{Name: "nightly_invoices", RunID: "run-1", Schedule: "0 2 * * *"}
+	err := JobRun(job, func() error {
+		clock = clock.Add(time.Minute)
+		return errors.New("smtp unavailable")
+	})
+
+	if got, want := err.Error(), "TestJobRun: smtp unavailable"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	wantFields := map[string]interface{}{
+		KeyJobName:     "nightly_invoices",
+		KeyJobRunID:    "run-1",
+		KeyJobSchedule: "0 2 * * *",
+	}
+	if got := ErrorFields(err); !reflect.DeepEqual(got, wantFields) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, wantFields)
+	}
+
+	wantEvents := []JobEvent{
+		{Job: job, Phase: JobStarted, Time: start},
+		{Job: job, Phase: JobFinished, Time: start.Add(time.Minute), Duration: time.Minute, Err: err},
+	}
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", events, wantEvents)
+	}
+
+	events = nil
+	if err := JobRun(Job{Name: "ok"}, func() error { return nil }); err != nil {
+		t.Errorf("expected nil but got %v", err)
+	}
+	if len(events) != 2 || events[1].Err != nil || events[0].Job.RunID == "" {
+		t.Errorf("expected successful run with generated run ID but got %+v", events)
+	}
+}
@@ -0,0 +1,76 @@
+package e
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// FromJSONError converts errors returned by encoding/json while decoding input
+// into an Error with code CodeValidation and a user-friendly message naming the
+// offending field or offset. Other errors, such as *json.InvalidUnmarshalError,
+// are only wrapped since they are not caused by the input.
+//
+// Usage:
+//
+//	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+//		return e.FromJSONError(err)
+//	}
+func FromJSONError(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	converted := wrap(err, 3)
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		converted.err = fmt.Errorf("(offset %d): %w", syntaxErr.Offset, err) // localizer.Ignore
+		converted.code = CodeValidation
+		converted.message = fmt.Sprintf("Malformed JSON at position %d.", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		converted.code = CodeValidation
+		if typeErr.Field != "" {
+			converted.message = fmt.Sprintf("Field %q must be %s.", typeErr.Field, jsonKind(typeErr.Type))
+		} else {
+			converted.message = fmt.Sprintf("Body must be %s.", jsonKind(typeErr.Type))
+		}
+	case errors.Is(err, io.EOF):
+		converted.code = CodeValidation
+		converted.message = "Body must not be empty."
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		converted.code = CodeValidation
+		converted.message = "Malformed JSON."
+	}
+	return converted.withText()
+}
+
+// jsonKind describes the JSON value expected for t.
+func jsonKind(t reflect.Type) string {
+	if t == nil {
+		return "a valid value"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Ptr:
+		return jsonKind(t.Elem())
+	}
+	return "a valid value"
+}
@@ -0,0 +1,76 @@
+package e
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFromJSONError(t *testing.T) {
+	type request struct {
+		Age  int      `json:"age"`
+		Tags []string `json:"tags"`
+	}
+	tests := []struct {
+		name        string
+		body        string
+		wantCode    Code
+		wantMessage string
+	}{
+		{
+			name:        "syntax error",
+			body:        `{"age": }`,
+			wantCode:    CodeValidation,
+			wantMessage: "Malformed JSON at position 9.",
+		},
+		{
+			name:        "type error",
+			body:        `{"age": "ten"}`,
+			wantCode:    CodeValidation,
+			wantMessage: `Field "age" must be a number.`,
+		},
+		{
+			name:        "type error on array",
+			body:        `{"tags": "a"}`,
+			wantCode:    CodeValidation,
+			wantMessage: `Field "tags" must be an array.`,
+		},
+		{
+			name:        "empty body",
+			body:        ``,
+			wantCode:    CodeValidation,
+			wantMessage: "Body must not be empty.",
+		},
+		{
+			name:        "truncated body",
+			body:        `{"age": 1`,
+			wantCode:    CodeValidation,
+			wantMessage: "Malformed JSON.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req request
+			err := FromJSONError(json.NewDecoder(strings.NewReader(tt.body)).Decode(&req))
+			if got := ErrorCode(err); got != tt.wantCode {
+				t.Errorf("code\ngot:  %q\nwant: %q", got, tt.wantCode)
+			}
+			if got := ErrorMessage(err); got != tt.wantMessage {
+				t.Errorf("message\ngot:  %q\nwant: %q", got, tt.wantMessage)
+			}
+		})
+	}
+
+	t.Run("non-input errors are only wrapped", func(t *testing.T) {
+		err := FromJSONError(errors.New("connection reset"))
+		if got := ErrorCode(err); got != "" {
+			t.Errorf("expected no code but got %q", got)
+		}
+	})
+	t.Run("nil stays nil", func(t *testing.T) {
+		if FromJSONError(nil) != nil {
+			t.Errorf("expected nil")
+		}
+	})
+}
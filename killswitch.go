@@ -0,0 +1,80 @@
+package e
+
+import (
+	"sync"
+	"time"
+)
+
+// KillSwitch returns a hook for AddHook which calls trip when errors with code
+// are created threshold times within window, e.g. to disable the feature flag
+// guarding a misbehaving code path. trip is called once, on the goroutine which
+// created the error, and again only after the rate has fallen back below the
+// threshold. The time comes from the clock set with SetClock.
+//
+// Like every hook, it only sees errors which start a new error stack, with the
+// code they were created with, and never sees quiet codes.
+//
+// Usage:
+//
+//	remove := e.AddHook(e.KillSwitch("payment_provider_error", 50, time.Minute,
+//		func(code e.Code, count int) {
+//			flags.Disable("new-checkout")
+//			log.Printf("disabled new-checkout after %d %s errors", count, code)
+//		}))
+//	defer remove()
+func KillSwitch(code Code, threshold int, window time.Duration, trip func(code Code, count int)) func(Error) {
+	if threshold < 1 {
+		threshold = 1
+	}
+	k := &killSwitch{
+		threshold: threshold,
+		window:    window,
+		events:    make([]time.Time, threshold),
+	}
+	return func(err Error) {
+		if rawCode(err) != code {
+			return
+		}
+		if k.record(now()) {
+			trip(code, threshold)
+		}
+	}
+}
+
+// killSwitch is the rate-tracking state of KillSwitch.
+type killSwitch struct {
+	threshold int
+	window    time.Duration
+
+	mu sync.Mutex
+	// events is a ring buffer of the times of the last threshold errors.
+	events  []time.Time
+	next    int
+	count   int
+	tripped bool
+}
+
+// record adds an error at t and reports whether the switch has just tripped.
+func (k *killSwitch) record(t time.Time) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.events[k.next] = t
+	k.next = (k.next + 1) % k.threshold
+	if k.count < k.threshold {
+		k.count++
+	}
+
+	// The oldest of the last threshold events is the one which will be
+	// overwritten next.
+	over := k.count == k.threshold && t.Sub(k.events[k.next]) <= k.window
+	if !over {
+		k.tripped = false
+		return false
+	}
+	if k.tripped {
+		return false
+	}
+	k.tripped = true
+	return true
+}
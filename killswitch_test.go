@@ -0,0 +1,49 @@
+package e
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKillSwitch(t *testing.T) {
+	skipSmallFootprint(t)
+
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	SetClock(func() time.Time { return clock })
+	defer SetClock(nil)
+
+	var trips []int
+	remove := AddHook(KillSwitch(CodeDatabase, 3, time.Minute, func(code Code, count int) {
+		if code != CodeDatabase {
+			t.Errorf("unexpected code %q", code)
+		}
+		trips = append(trips, count)
+	}))
+	defer remove()
+
+	tick := func(d time.Duration, codes ...Code) {
+		for _, code := range codes {
+			clock = clock.Add(d)
+			NewError(code, "cannot foo")
+		}
+	}
+
+	tick(time.Second, CodeDatabase, CodeDatabase, CodeInternal)
+	if len(trips) != 0 {
+		t.Fatalf("expected no trips below threshold but got %v", trips)
+	}
+	tick(time.Second, CodeDatabase)
+	if len(trips) != 1 || trips[0] != 3 {
+		t.Fatalf("expected one trip but got %v", trips)
+	}
+	tick(time.Second, CodeDatabase, CodeDatabase)
+	if len(trips) != 1 {
+		t.Fatalf("expected no trips while tripped but got %v", trips)
+	}
+	tick(time.Minute, CodeDatabase)
+	tick(time.Second, CodeDatabase, CodeDatabase)
+	if len(trips) != 2 {
+		t.Fatalf("expected trip after rate recovered but got %v", trips)
+	}
+}
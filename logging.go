@@ -0,0 +1,91 @@
+package e
+
+// Canonical keys used when encoding an Error as structured fields. Adapters and
+// custom encoders should use these so dashboards can rely on stable names.
+const (
+	KeyCode    = "code"
+	KeyMessage = "message"
+	KeyErrorID = "error_id"
+	KeyOp      = "op"
+	KeyStack   = "stacktrace"
+
+	// KeyCauseTypes holds the result of CauseTypes.
+	KeyCauseTypes = "cause_types"
+
+	// KeyOriginalCode holds the code replaced by MapCodes.
+	KeyOriginalCode = "original_code"
+
+	// KeyDeadlineRemaining and KeyDeadlineOverrun are set by WrapDeadline.
+	KeyDeadlineRemaining = "deadline_remaining"
+	KeyDeadlineOverrun   = "deadline_overrun"
+
+	// KeyOffset and KeyItem are set by WrapAt and Stream.Wrap.
+	KeyOffset = "offset"
+	KeyItem   = "item"
+
+	// KeyCoalescedCallers is set by Singleflight.
+	KeyCoalescedCallers = "coalesced_callers"
+
+	// KeyJobName, KeyJobRunID and KeyJobSchedule are set by JobRun.
+	KeyJobName     = "job"
+	KeyJobRunID    = "job_run_id"
+	KeyJobSchedule = "job_schedule"
+
+	// KeyGoroutine and KeyProfileLabels are set when SetGoroutineInfo is
+	// enabled.
+	KeyGoroutine     = "goroutine"
+	KeyProfileLabels = "pprof_labels"
+
+	// KeyHostname, KeyPod, KeyRegion and KeyPID are set by SetHostInfo.
+	KeyHostname = "hostname"
+	KeyPod      = "pod"
+	KeyRegion   = "region"
+	KeyPID      = "pid"
+)
+
+// KeysAndValues returns the structured data of err as alternating key/value
+// pairs, in the form expected by logr-based loggers such as controller-runtime
+// and klog. Fields from ErrorFields follow the canonical keys, sorted by key.
+// Empty values are omitted, as is the stacktrace of errors marked with
+// SetNoStackExport.
+//
+// Usage:
+//
+//	if err := r.reconcile(ctx, req); err != nil {
+//		log.Error(err, "reconcile failed", e.KeysAndValues(err)...)
+//	}
+func KeysAndValues(err error) []interface{} {
+	if err == nil {
+		return nil
+	}
+
+	var kv []interface{}
+	if code := ErrorCode(err); code != "" {
+		kv = append(kv, KeyCode, string(code))
+	}
+	if id := ErrorID(err); id != "" {
+		kv = append(kv, KeyErrorID, id)
+	}
+	if msg := ErrorMessage(err); msg != "" {
+		kv = append(kv, KeyMessage, msg)
+	}
+	if ops := ErrorOps(err); len(ops) > 0 {
+		kv = append(kv, KeyOp, ops)
+	}
+	if types := CauseTypes(err); len(types) > 0 {
+		kv = append(kv, KeyCauseTypes, types)
+	}
+	if stack := ErrorStacktrace(err); stack != "" && StackExportable(err) {
+		kv = append(kv, KeyStack, stack)
+	}
+	fields := ErrorFields(err)
+	for _, key := range sortedKeys(fields) {
+		switch key {
+		case KeyCode, KeyMessage, KeyErrorID, KeyOp, KeyStack, KeyCauseTypes:
+			// canonical keys cannot be overridden by fields
+		default:
+			kv = append(kv, key, fields[key])
+		}
+	}
+	return kv
+}
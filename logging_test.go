@@ -0,0 +1,72 @@
+package e
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestKeysAndValues(t *testing.T) {
+	skipNoCapture(t)
+
+	t.Run("nil error returns nil", func(t *testing.T) {
+		if kv := KeysAndValues(nil); kv != nil {
+			t.Fatalf("expected nil but got %v", kv)
+		}
+	})
+	t.Run("non-pkg error returns nothing", func(t *testing.T) {
+		if kv := KeysAndValues(errors.New("basic error")); len(kv) != 0 {
+			t.Fatalf("expected no pairs but got %v", kv)
+		}
+	})
+	t.Run("returns code, error ID, message and ops", func(t *testing.T) {
+		err := Wrap(Foo()).SetMessage("oh no").SetID("abc")
+		kv := KeysAndValues(err)
+		want := []interface{}{
+			KeyCode, CodeDatabase,
+			KeyErrorID, "abc",
+			KeyMessage, "oh no",
+			KeyOp, []string{"TestKeysAndValues.func3", "Foo"},
+		}
+		if len(kv) != len(want)+2 {
+			t.Fatalf("expected %d values but got %v", len(want)+2, kv)
+		}
+		if !reflect.DeepEqual(kv[:len(want)], want) {
+			t.Errorf("\ngot:  %v\nwant: %v", kv[:len(want)], want)
+		}
+		if kv[len(want)] != KeyStack || kv[len(want)+1] == "" {
+			t.Errorf("expected stacktrace pair but got %v", kv[len(want):])
+		}
+	})
+	t.Run("omits stacktrace marked as not exportable", func(t *testing.T) {
+		err := Wrap(Foo().(Error).SetNoStackExport())
+		kv := KeysAndValues(err)
+		for i := 0; i < len(kv); i += 2 {
+			if kv[i] == KeyStack {
+				t.Fatalf("expected no stacktrace but got %v", kv)
+			}
+		}
+		if ErrorStacktrace(err) == "" {
+			t.Errorf("expected stacktrace to remain available locally")
+		}
+	})
+	t.Run("appends sorted fields", func(t *testing.T) {
+		err := NewError("", "cannot foo").SetNoStackExport().SetID("abc").
+			SetFields(map[string]interface{}{"b": 2, "a": 1, KeyCode: "ignored"})
+		want := []interface{}{KeyErrorID, "abc", KeyOp, []string{"TestKeysAndValues.func5"}, "a", 1, "b", 2}
+		if got := KeysAndValues(err); !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+	})
+}
+
+func TestKeysAndValuesCauseTypes(t *testing.T) {
+	skipNoCapture(t)
+
+	err := Wrap(&os.PathError{Op: "open", Path: "/tmp", Err: os.ErrNotExist}).SetID("")
+	kv := KeysAndValues(err)
+	if kv[2] != KeyCauseTypes || !reflect.DeepEqual(kv[3], []string{"*fs.PathError"}) {
+		t.Errorf("expected cause types but got %v", kv)
+	}
+}
@@ -0,0 +1,90 @@
+package e
+
+import (
+	"sync"
+	"time"
+)
+
+// Memo caches the error returned by an operation for a fixed TTL, keyed by a
+// fingerprint of the operation's input (negative caching). Repeated identical
+// failing calls, such as lookups of a missing ID, return the cached Error
+// without doing the downstream work again. Successful calls are never cached.
+//
+// A Memo must be created with NewMemo and is safe for concurrent use.
+type Memo struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]memoEntry
+	lastSweep time.Time
+}
+
+type memoEntry struct {
+	err     error
+	expires time.Time
+}
+
+// NewMemo returns a Memo which caches errors for ttl.
+func NewMemo(ttl time.Duration) *Memo {
+	return &Memo{
+		ttl:     ttl,
+		entries: make(map[string]memoEntry),
+	}
+}
+
+// Do returns the cached error for key if it has not expired. Otherwise it calls
+// fn and caches the returned error, if any. Errors which are not already an
+// Error are wrapped so that cached results are always fully formed.
+//
+// Usage:
+//
+//	var user *User
+//	err := userMemo.Do(id, func() error {
+//		var err error
+//		user, err = db.GetUser(id)
+//		return err
+//	})
+func (m *Memo) Do(key string, fn func() error) error {
+	now := now()
+
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	m.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.err
+	}
+
+	err := fn()
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(Error); !ok {
+		err = wrap(err, 3)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Sub(m.lastSweep) > m.ttl {
+		m.sweep(now)
+	}
+	m.entries[key] = memoEntry{err: err, expires: now.Add(m.ttl)}
+	return err
+}
+
+// Forget removes any cached error for key.
+func (m *Memo) Forget(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// sweep drops expired entries so keys which are never looked up again do not
+// accumulate. Must be called with mu held.
+func (m *Memo) sweep(now time.Time) {
+	for key, entry := range m.entries {
+		if !now.Before(entry.expires) {
+			delete(m.entries, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -0,0 +1,94 @@
+package e
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemo(t *testing.T) {
+	skipNoCapture(t)
+
+	t.Run("caches errors until expiry", func(t *testing.T) {
+		m := NewMemo(time.Hour)
+		calls := 0
+		fn := func() error {
+			calls++
+			return errors.New("not found")
+		}
+
+		err1 := m.Do("id-1", fn)
+		err2 := m.Do("id-1", fn)
+		if calls != 1 {
+			t.Fatalf("expected 1 call but got %d", calls)
+		}
+		if err1 != err2 {
+			t.Errorf("expected cached error to be returned")
+		}
+		if got, want := err1.Error(), "TestMemo.func1: not found"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+
+		m.Do("id-2", fn)
+		if calls != 2 {
+			t.Errorf("expected different key to call fn but got %d calls", calls)
+		}
+	})
+	t.Run("expires with clock", func(t *testing.T) {
+		current := time.Unix(0, 0)
+		SetClock(func() time.Time { return current })
+		defer SetClock(nil)
+
+		m := NewMemo(time.Minute)
+		calls := 0
+		fn := func() error {
+			calls++
+			return Foo()
+		}
+		m.Do("id", fn)
+		current = current.Add(59 * time.Second)
+		m.Do("id", fn)
+		if calls != 1 {
+			t.Errorf("expected cached error before expiry but got %d calls", calls)
+		}
+		current = current.Add(time.Second)
+		m.Do("id", fn)
+		if calls != 2 {
+			t.Errorf("expected expired entry to call fn but got %d calls", calls)
+		}
+	})
+	t.Run("does not cache success", func(t *testing.T) {
+		m := NewMemo(time.Hour)
+		calls := 0
+		fn := func() error {
+			calls++
+			return nil
+		}
+		m.Do("id", fn)
+		m.Do("id", fn)
+		if calls != 2 {
+			t.Errorf("expected 2 calls but got %d", calls)
+		}
+	})
+	t.Run("expired and forgotten entries are recomputed", func(t *testing.T) {
+		m := NewMemo(0)
+		calls := 0
+		fn := func() error {
+			calls++
+			return NewError(CodeDatabase, "cannot foo")
+		}
+		m.Do("id", fn)
+		m.Do("id", fn)
+		if calls != 2 {
+			t.Errorf("expected expired entry to call fn but got %d calls", calls)
+		}
+
+		m = NewMemo(time.Hour)
+		m.Do("id", fn)
+		m.Forget("id")
+		m.Do("id", fn)
+		if calls != 4 {
+			t.Errorf("expected forgotten entry to call fn but got %d calls", calls)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package e
+
+// Meta holds typed attributes describing one hop of the logical stack, so
+// layers can be described without free-text optionalInfo strings.
+type Meta struct {
+	// Component is the layer which wrapped the error, e.g. "repo" or "handler".
+	Component string
+
+	// Table is the database table involved, if any.
+	Table string
+}
+
+// WrapMeta wraps err like Wrap and attaches meta to the new hop. Use Walk with
+// MetaOf to read the metadata of every hop.
+//
+// Usage:
+//
+//	err := db.QueryRow(...).Scan(...)
+//	if err != nil {
+//		return e.WrapMeta(err, e.Meta{Component: "repo", Table: "users"})
+//	}
+func WrapMeta(err error, meta Meta) Error {
+	if err == nil {
+		return nil
+	}
+
+	wrapped := wrap(err, 3)
+	wrapped.meta = &meta
+	return wrapped
+}
+
+// MetaOf returns the Meta attached to err itself by WrapMeta. Wrapped errors are
+// not inspected; use Walk to visit every hop.
+//
+// Usage:
+//
+//	e.Walk(err, func(err error) bool {
+//		if meta, ok := e.MetaOf(err); ok {
+//			log.Printf("component=%s table=%s", meta.Component, meta.Table)
+//		}
+//		return true
+//	})
+func MetaOf(err error) (Meta, bool) {
+	if e, ok := err.(errorImpl); ok && e.meta != nil {
+		return *e.meta, true
+	}
+	return Meta{}, false
+}
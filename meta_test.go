@@ -0,0 +1,32 @@
+package e
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapMeta(t *testing.T) {
+	skipNoCapture(t)
+
+	repo := WrapMeta(Foo(), Meta{Component: "repo", Table: "users"})
+	err := WrapMeta(Wrap(repo), Meta{Component: "service"})
+
+	var metas []Meta
+	Walk(err, func(err error) bool {
+		if meta, ok := MetaOf(err); ok {
+			metas = append(metas, meta)
+		}
+		return true
+	})
+
+	want := []Meta{{Component: "service"}, {Component: "repo", Table: "users"}}
+	if !reflect.DeepEqual(metas, want) {
+		t.Errorf("\ngot:  %+v\nwant: %+v", metas, want)
+	}
+	if got, want := repo.Error(), "TestWrapMeta: Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if WrapMeta(nil, Meta{}) != nil {
+		t.Errorf("expected nil")
+	}
+}
@@ -0,0 +1,31 @@
+package e
+
+// SetOpLines makes ops include the file and line of the call which created or
+// wrapped the error, e.g. "Foo@foo.go:12", so that several wrap points inside
+// one long function can be told apart. Error() and ErrorOps show the longer
+// ops; note that Fingerprint then changes whenever the code moves.
+//
+// Usage:
+//
+//	e.SetOpLines(true)
+//	// "GetBar@bar.go:42: GetBar@bar.go:17: [database_error] cannot get bar"
+func SetOpLines(enabled bool) {
+	Configure(func(c *Config) {
+		c.OpLines = enabled
+	})
+}
+
+// OpLine makes a Builder include the file and line in the op like SetOpLines,
+// for a single call.
+func OpLine() Option {
+	return func(b *Builder) { b.opLine = true }
+}
+
+// callingOp returns the op of the calling function according to c. frameOffset
+// counts frames like getCallingFunc from the caller of callingOp.
+func (c *Config) callingOp(frameOffset int) string {
+	if c.OpLines {
+		return getCallingFuncLine(1 + frameOffset)
+	}
+	return getCallingFunc(1 + frameOffset)
+}
@@ -0,0 +1,32 @@
+package e
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSetOpLines(t *testing.T) {
+	skipNoCapture(t)
+
+	SetOpLines(true)
+	err := Bar()
+	SetOpLines(false)
+
+	want := []string{"Bar@error_test.go:115", "Foo@error_test.go:109"}
+	if got := ErrorOps(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+	if got := ErrorOps(Bar()); !reflect.DeepEqual(got, []string{"Bar", "Foo"}) {
+		t.Errorf("expected ops without lines once disabled but got %v", got)
+	}
+}
+
+func TestOpLine(t *testing.T) {
+	skipNoCapture(t)
+
+	err := With(OpLine()).NewError(CodeDatabase, "cannot foo")
+	if got := ErrorOps(err); len(got) != 1 || !regexp.MustCompile(`^TestOpLine@opline_test\.go:\d+$`).MatchString(got[0]) {
+		t.Errorf("expected op with line but got %v", got)
+	}
+}
@@ -0,0 +1,136 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+)
+
+// defaultStackDepth is the number of frames captured when Config.MaxStackDepth
+// is not set.
+const defaultStackDepth = 64
+
+// smallStackDepth caps the number of frames captured when building with the
+// e_small tag.
+const smallStackDepth = 8
+
+// SetMaxStackDepth caps the number of frames captured for new error stacks,
+// keeping deep call stacks bounded in size. depth <= 0 restores the default of
+// 64 frames. Builds with the e_small tag never capture more than 8 frames.
+func SetMaxStackDepth(depth int) {
+	Configure(func(c *Config) {
+		c.MaxStackDepth = depth
+	})
+}
+
+// stackDepth returns the number of frames to capture according to c.
+func (c *Config) stackDepth() int {
+	depth := defaultStackDepth
+	if c.MaxStackDepth > 0 {
+		depth = c.MaxStackDepth
+	}
+	if smallFootprint && depth > smallStackDepth {
+		return smallStackDepth
+	}
+	return depth
+}
+
+// Option customizes how errors are constructed by a Builder.
+type Option func(*Builder)
+
+// StackDepth caps the number of frames captured, overriding SetMaxStackDepth.
+func StackDepth(depth int) Option {
+	return func(b *Builder) { b.depth = depth }
+}
+
+// Skip skips n additional frames when deriving the op and capturing the stack,
+// so that functions which construct errors on behalf of their callers report
+// the caller instead of themselves. See also Helper.
+func Skip(n int) Option {
+	return func(b *Builder) { b.skip += n }
+}
+
+// WithoutStack skips stack capture, for hot paths such as validation where the
+// code and message are sufficient. The op is still derived, hooks are still
+// called and Wrap does not capture a stack for the error later.
+func WithoutStack() Option {
+	return func(b *Builder) { b.noStack = true }
+}
+
+// Builder constructs errors like the package-level constructors with Options
+// applied. The zero Builder behaves exactly like them.
+type Builder struct {
+	skip  int
+	depth int
+
+	noStack bool
+	opLine  bool
+}
+
+// With returns a Builder which applies opts to every error it constructs.
+//
+// Usage:
+//
+//	func notFound(what string) error {
+//		return e.With(e.Skip(1)).NewError("not_exists", what+" not found")
+//	}
+func With(opts ...Option) Builder {
+	var b Builder
+	for _, opt := range opts {
+		opt(&b)
+	}
+	return b
+}
+
+// config returns the active Config with the settings of b applied.
+func (b Builder) config() *Config {
+	c := loadConfig()
+	if b.depth <= 0 && !b.noStack && !b.opLine {
+		return c
+	}
+	custom := *c
+	if b.depth > 0 {
+		custom.MaxStackDepth = b.depth
+	}
+	custom.noStack = b.noStack
+	custom.OpLines = custom.OpLines || b.opLine
+	return &custom
+}
+
+// NewError is like the package-level NewError.
+func (b Builder) NewError(code Code, cause string) Error {
+	return newErrorIn(b.config(), 3+b.skip, code, errors.New(cause))
+}
+
+// NewErrorf is like the package-level NewErrorf.
+func (b Builder) NewErrorf(code Code, fmtCause string, args ...interface{}) Error {
+	e := newErrorIn(b.config(), 3+b.skip, code, fmt.Errorf(fmtCause, args...))
+	checkFormat(e.op, e.err.Error())
+	return e
+}
+
+// Wrap is like the package-level Wrap.
+func (b Builder) Wrap(err error, optionalInfo ...string) Error {
+	if err == nil {
+		return nil
+	}
+
+	innerErr := err
+	if len(optionalInfo) > 0 {
+		innerErr = fmt.Errorf("(%v): %w", optionalInfo[0], err) // localizer.Ignore
+	}
+
+	return wrapIn(b.config(), innerErr, 3+b.skip)
+}
+
+// NewErrorNoTrace is like NewError but does not capture a stack, like
+// With(WithoutStack()).NewError. It is intended for errors created so often
+// that stack capture is too costly.
+//
+// Usage:
+//
+//	if !emailPattern.MatchString(email) {
+//		return e.NewErrorNoTrace("validation_error", "invalid email")
+//	}
+func NewErrorNoTrace(code Code, cause string) Error {
+	return newErrorIn(Builder{noStack: true}.config(), 3, code, errors.New(cause))
+}
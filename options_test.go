@@ -0,0 +1,92 @@
+package e
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newNotFound(what string) error {
+	return With(Skip(1)).NewError("not_exists", what+" not found")
+}
+
+func wrapBasic() error {
+	return With(Skip(1)).Wrap(errors.New("basic error"))
+}
+
+func TestWith(t *testing.T) {
+	skipNoCapture(t)
+
+	tests := []struct {
+		name   string
+		err    error
+		wantOp string
+	}{
+		{name: "zero Builder reports caller", err: With().NewErrorf("not_exists", "%s not found", "bar"), wantOp: "TestWith"},
+		{name: "NewError skips helper", err: newNotFound("bar"), wantOp: "TestWith"},
+		{name: "Wrap skips helper", err: wrapBasic(), wantOp: "TestWith"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorOps(tt.err); len(got) != 1 || got[0] != tt.wantOp {
+				t.Errorf("expected op %q but got %v", tt.wantOp, got)
+			}
+			frames := ErrorStackFrames(tt.err)
+			if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "."+tt.wantOp) {
+				t.Errorf("expected stack starting at %s but got %v", tt.wantOp, frames)
+			}
+		})
+	}
+
+	if got := With().Wrap(nil); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+}
+
+func TestStackDepth(t *testing.T) {
+	skipNoCapture(t)
+
+	if got := len(ErrorStackFrames(With(StackDepth(2)).NewError(CodeDatabase, "cannot foo"))); got != 2 {
+		t.Errorf("expected 2 frames but got %d", got)
+	}
+
+	SetMaxStackDepth(3)
+	defer SetMaxStackDepth(0)
+	if got := len(ErrorStackFrames(Foo())); got != 3 {
+		t.Errorf("expected 3 frames but got %d", got)
+	}
+	if got := len(ErrorStackFrames(With(StackDepth(1)).Wrap(errors.New("basic error")))); got != 1 {
+		t.Errorf("expected per-call depth to override but got %d frames", got)
+	}
+}
+
+func TestWithoutStack(t *testing.T) {
+	skipNoCapture(t)
+	skipSmallFootprint(t)
+
+	var hooked int
+	remove := AddHook(func(Error) { hooked++ })
+	defer remove()
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "NewErrorNoTrace", err: NewErrorNoTrace(CodeValidation, "invalid email")},
+		{name: "Builder", err: With(WithoutStack()).Wrap(errors.New("basic error"))},
+		{name: "wrapped", err: Wrap(NewErrorNoTrace(CodeValidation, "invalid email"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorStacktrace(tt.err); got != "" {
+				t.Errorf("expected no stacktrace but got %q", got)
+			}
+			if got := ErrorOps(tt.err); len(got) == 0 || got[len(got)-1] != "TestWithoutStack" {
+				t.Errorf("expected op of caller but got %v", got)
+			}
+		})
+	}
+	if hooked != 3 {
+		t.Errorf("expected hooks to be called once per error stack but got %d calls", hooked)
+	}
+}
@@ -0,0 +1,29 @@
+package e
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which buffers are not returned to
+// bufferPool, so that one huge error does not pin memory for the process.
+const maxPooledBuffer = 64 << 10
+
+// bufferPool holds the buffers used to render the text of errors and stacks,
+// cutting allocation churn when services produce bursts of errors.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns buf to bufferPool. buf must not be used afterwards.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
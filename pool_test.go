@@ -0,0 +1,61 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPutBuffer(t *testing.T) {
+	t.Run("resets pooled buffers", func(t *testing.T) {
+		buf := getBuffer()
+		buf.WriteString("leaked")
+		putBuffer(buf)
+
+		got := getBuffer()
+		defer putBuffer(got)
+		if got.Len() != 0 {
+			t.Errorf("expected an empty buffer but got %q", got.String())
+		}
+	})
+	t.Run("drops oversized buffers", func(t *testing.T) {
+		buf := getBuffer()
+		buf.Grow(maxPooledBuffer + 1)
+		putBuffer(buf)
+
+		got := getBuffer()
+		defer putBuffer(got)
+		if got == buf || got.Cap() > maxPooledBuffer {
+			t.Errorf("expected buffer of %d bytes not to be pooled", buf.Cap())
+		}
+	})
+}
+
+func TestPooledRenderDoesNotLeak(t *testing.T) {
+	skipNoCapture(t)
+
+	long := NewError(CodeDatabase, strings.Repeat("x", 1024))
+	if got := long.Error(); got != "TestPooledRenderDoesNotLeak: [database_error] "+strings.Repeat("x", 1024) {
+		t.Fatalf("unexpected text %q", got)
+	}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				cause := fmt.Sprintf("cause %d.%d", i, n)
+				err := Wrap(errors.New(cause))
+				if got, want := err.Error(), "TestPooledRenderDoesNotLeak.func1: "+cause; got != want {
+					t.Errorf("\ngot:  %q\nwant: %q", got, want)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,102 @@
+package e
+
+import (
+	"errors"
+	"strings"
+)
+
+// Predicate reports whether an error matches a condition. Predicates are built
+// with CodeIs, Retryable and the other constructors below and combined with
+// And, Or and Not, so that routing, retry and alerting rules can be declared
+// once and reused across subsystems.
+type Predicate func(err error) bool
+
+// Match reports whether err is non-nil and matches p.
+//
+// Usage:
+//
+//	var transient = e.CodeIs("timeout").Or(e.Retryable()).And(e.OpContains("Repo"))
+//
+//	if e.Match(err, transient) {
+//		return retry(ctx, op)
+//	}
+func Match(err error, p Predicate) bool {
+	return err != nil && p != nil && p(err)
+}
+
+// And returns a Predicate which matches errors matching p and every one of
+// others.
+func (p Predicate) And(others ...Predicate) Predicate {
+	return func(err error) bool {
+		if !p(err) {
+			return false
+		}
+		for _, other := range others {
+			if !other(err) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a Predicate which matches errors matching p or any of others.
+func (p Predicate) Or(others ...Predicate) Predicate {
+	return func(err error) bool {
+		if p(err) {
+			return true
+		}
+		for _, other := range others {
+			if other(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Predicate which matches errors not matching p.
+func (p Predicate) Not() Predicate {
+	return func(err error) bool {
+		return !p(err)
+	}
+}
+
+// CodeIs matches errors whose outermost code is one of codes, like IsAnyCode.
+func CodeIs(codes ...Code) Predicate {
+	return func(err error) bool {
+		return IsAnyCode(err, codes...)
+	}
+}
+
+// CodeUnder matches errors whose outermost code is prefix or one of its
+// descendants, like CodeHasPrefix.
+func CodeUnder(prefix Code) Predicate {
+	return func(err error) bool {
+		return CodeHasPrefix(err, prefix)
+	}
+}
+
+// Retryable matches errors which may be retried, like IsRetryable.
+func Retryable() Predicate {
+	return IsRetryable
+}
+
+// OpContains matches errors with an op in their chain containing substr.
+func OpContains(substr string) Predicate {
+	return func(err error) bool {
+		for _, op := range ErrorOps(err) {
+			if strings.Contains(op, substr) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Wraps matches errors wrapping target, like errors.Is.
+func Wraps(target error) Predicate {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
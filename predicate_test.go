@@ -0,0 +1,42 @@
+package e
+
+import (
+	"io"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	skipNoCapture(t)
+
+	tests := []struct {
+		name string
+		err  error
+		p    Predicate
+		want bool
+	}{
+		{name: "nil error never matches", err: nil, p: CodeIs("").Not(), want: false},
+		{name: "nil predicate never matches", err: Foo(), p: nil, want: false},
+		{name: "CodeIs", err: Bar(), p: CodeIs(CodeInternal, CodeDatabase), want: true},
+		{name: "CodeIs other code", err: Bar(), p: CodeIs(CodeInternal), want: false},
+		{name: "CodeUnder", err: NewError("database.connection.timeout", "cannot connect"), p: CodeUnder("database"), want: true},
+		{name: "Retryable", err: Wrap(Foo()).SetRetryable(true), p: Retryable(), want: true},
+		{name: "OpContains", err: Bar(), p: OpContains("Fo"), want: true},
+		{name: "Wraps", err: Wrap(io.EOF), p: Wraps(io.EOF), want: true},
+		{name: "Or", err: Foo(), p: CodeIs(CodeTimeout).Or(Retryable(), OpContains("Foo")), want: true},
+		{name: "And", err: Foo(), p: CodeIs(CodeDatabase).And(OpContains("Foo"), Retryable()), want: false},
+		{name: "Not", err: Foo(), p: Retryable().Not(), want: true},
+		{
+			name: "composed",
+			err:  Wrap(Foo()).SetCode(CodeTimeout),
+			p:    CodeIs(CodeTimeout).Or(Retryable()).And(OpContains("Foo")),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.err, tt.p); got != tt.want {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Rule maps external errors to a code and message for use with Promote.
+// A Rule matches when err matches Is (using errors.Is) or As (using errors.As).
+type Rule struct {
+	// Is is a target error such as sql.ErrNoRows.
+	Is error
+
+	// As is a pointer to a variable of the target type, as would be passed to
+	// errors.As, e.g. new(*os.PathError). The variable itself is never written.
+	As interface{}
+
+	// Code and Message are set on the promoted Error when non-empty.
+	Code    Code
+	Message string
+
+	// Retryable, if not nil, marks the promoted Error like SetRetryable.
+	Retryable *bool
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// validate returns why As cannot be passed to errors.As, or "" if it can.
+func (r Rule) validate() string {
+	if r.As == nil {
+		return ""
+	}
+	t := reflect.TypeOf(r.As)
+	if t.Kind() != reflect.Ptr {
+		return fmt.Sprintf("Rule.As must be a pointer but got %T", r.As)
+	}
+	if t.Elem().Kind() != reflect.Interface && !t.Elem().Implements(errorType) {
+		return fmt.Sprintf("Rule.As must point to an interface or a type implementing error but got %T", r.As)
+	}
+	return ""
+}
+
+// validateRules reports a contract violation for each Rule of rules which is
+// invalid. Invalid rules never match.
+func validateRules(op string, rules []Rule) {
+	for _, rule := range rules {
+		if problem := rule.validate(); problem != "" {
+			reportViolation(op, problem)
+		}
+	}
+}
+
+func (r Rule) matches(err error) bool {
+	if r.Is != nil && errors.Is(err, r.Is) {
+		return true
+	}
+	if r.As != nil && r.validate() == "" {
+		target := reflect.New(reflect.TypeOf(r.As).Elem()).Interface()
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Promote wraps err like Wrap and applies the code, message and retryability
+// of the first matching Rule, letting teams declare their external-error to
+// code mapping in one table and apply it consistently. If no rule matches, err
+// is only wrapped. Invalid rules are reported as contract violations.
+//
+// Usage:
+//
+//	var dbRules = []e.Rule{
+//		{Is: sql.ErrNoRows, Code: "not_exists"},
+//		{As: new(*pq.Error), Code: "database_error"},
+//	}
+//
+//	func GetBar(id string) error {
+//		err := db.QueryRow(...).Scan(...)
+//		if err != nil {
+//			return e.Promote(err, dbRules...)
+//		}
+//		return nil
+//	}
+func Promote(err error, rules ...Rule) Error {
+	if err == nil {
+		return nil
+	}
+
+	promoted := wrap(err, 3)
+	validateRules(promoted.op, rules)
+	return promoted.promote(matchRule(err, rules), rules)
+}
+
+// matchRule returns the index of the first Rule matching err, or -1.
+func matchRule(err error, rules []Rule) int {
+	for i, rule := range rules {
+		if rule.matches(err) {
+			return i
+		}
+	}
+	return -1
+}
+
+// promote applies rules[i], if i is a valid index.
+func (e errorImpl) promote(i int, rules []Rule) errorImpl {
+	if i < 0 || i >= len(rules) {
+		return e
+	}
+	rule := rules[i]
+	if rule.Code != "" {
+		loadConfig().validateCode(e.op, rule.Code)
+		e.code = rule.Code
+		e = e.withText()
+	}
+	if rule.Message != "" {
+		e.message = rule.Message
+	}
+	if rule.Retryable != nil {
+		retryable := *rule.Retryable
+		e.retryable = &retryable
+	}
+	return e
+}
+
+// MapCodes wraps err like Wrap and sets the code found in mapping for the
+// outermost code of err, if any. It is intended for gateways which aggregate
+// several backends with differing code taxonomies into one public scheme. The
+// replaced code is recorded in the KeyOriginalCode field.
+//
+// Usage:
+//
+//	var publicCodes = map[e.Code]e.Code{
+//		"not_exists":     "not_found",
+//		"database_error": "unavailable",
+//	}
+//
+//	resp, err := backend.Call(ctx, req)
+//	if err != nil {
+//		return e.MapCodes(err, publicCodes)
+//	}
+func MapCodes(err error, mapping map[Code]Code) Error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := wrap(err, 3)
+	original := rawCode(err)
+	if code, ok := mapping[original]; ok {
+		loadConfig().validateCode(mapped.op, code)
+		mapped.code = code
+		return mapped.withText().SetField(KeyOriginalCode, string(original))
+	}
+	return mapped
+}
@@ -0,0 +1,157 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+var errNoRows = errors.New("no rows")
+
+var testRules = []Rule{
+	{Is: errNoRows, Code: "not_exists", Message: "Not found"},
+	{As: new(*os.PathError), Code: CodeInternal},
+}
+
+func TestPromote(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    Code
+		wantMessage string
+	}{
+		{
+			name:        "matches Is rule",
+			err:         fmt.Errorf("query: %w", errNoRows),
+			wantCode:    "not_exists",
+			wantMessage: "Not found",
+		},
+		{
+			name:     "matches As rule",
+			err:      &os.PathError{Op: "open", Path: "/tmp", Err: os.ErrNotExist},
+			wantCode: CodeInternal,
+		},
+		{
+			name: "no match only wraps",
+			err:  errors.New("basic error"),
+		},
+		{
+			name:     "keeps inner code when no match",
+			err:      NewError(CodeDatabase, "cannot foo"),
+			wantCode: CodeDatabase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Promote(tt.err, testRules...)
+			if got := ErrorCode(err); got != tt.wantCode {
+				t.Errorf("code\ngot:  %q\nwant: %q", got, tt.wantCode)
+			}
+			if got := ErrorMessage(err); got != tt.wantMessage {
+				t.Errorf("message\ngot:  %q\nwant: %q", got, tt.wantMessage)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected promoted error to wrap original")
+			}
+		})
+	}
+
+	if Promote(nil, testRules...) != nil {
+		t.Errorf("expected nil error to stay nil")
+	}
+}
+
+func TestPromoteRetryable(t *testing.T) {
+	retryable, notRetryable := true, false
+	rules := []Rule{
+		{Is: errNoRows, Code: "not_exists", Retryable: &notRetryable},
+		{As: new(*os.PathError), Retryable: &retryable},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "marks retryable", err: &os.PathError{Op: "open", Path: "/tmp", Err: os.ErrNotExist}, want: true},
+		{name: "marks not retryable", err: Wrap(errNoRows).SetRetryable(true), want: false},
+		{name: "no match keeps inner retryability", err: NewError(CodeDatabase, "cannot foo").SetRetryable(true), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(Promote(tt.err, rules...)); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+			if got := IsRetryable(NewPromoter(1, rules...).Promote(tt.err)); got != tt.want {
+				t.Errorf("Promoter: IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromoteInvalidRule(t *testing.T) {
+	skipNoCapture(t)
+	skipSmallFootprint(t)
+
+	var violations []Error
+	remove := AddHook(func(err Error) {
+		if ErrorCode(err) == CodeContractViolation {
+			violations = append(violations, err)
+		}
+	})
+	defer remove()
+
+	rules := []Rule{
+		{As: os.PathError{}, Code: CodeInternal},
+		{As: new(string), Code: CodeInternal},
+		{Is: errNoRows, Code: "not_exists"},
+	}
+
+	err := Promote(fmt.Errorf("query: %w", errNoRows), rules...)
+	if got := ErrorCode(err); got != "not_exists" {
+		t.Errorf("expected valid rule to apply but got %q", got)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations but got %v", violations)
+	}
+	want := `TestPromoteInvalidRule: [contract_violation] Rule.As must be a pointer but got fs.PathError`
+	if got := violations[0].Error(); got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+
+	violations = nil
+	p := NewPromoter(1, rules...)
+	if len(violations) != 2 {
+		t.Fatalf("expected NewPromoter to report 2 violations but got %v", violations)
+	}
+	p.Promote(&os.PathError{Op: "open", Path: "/tmp", Err: os.ErrNotExist})
+	if len(violations) != 2 {
+		t.Errorf("expected Promote not to report violations again but got %v", violations)
+	}
+	if got := violations[1].Error(); got != `TestPromoteInvalidRule: [contract_violation] Rule.As must point to an interface or a type implementing error but got *string` {
+		t.Errorf("unexpected violation %q", got)
+	}
+}
+
+func TestMapCodes(t *testing.T) {
+	skipNoCapture(t)
+
+	mapping := map[Code]Code{CodeDatabase: "unavailable"}
+
+	err := MapCodes(Foo(), mapping)
+	if got, want := err.Error(), "TestMapCodes: [unavailable] Foo: [database_error] cannot foo"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	if got := ErrorFields(err)[KeyOriginalCode]; got != CodeDatabase {
+		t.Errorf("expected original code field but got %v", got)
+	}
+
+	err = MapCodes(NewError(CodeInternal, "cannot bar"), mapping)
+	if got := ErrorCode(err); got != CodeInternal {
+		t.Errorf("expected unmapped code to be kept but got %q", got)
+	}
+	if MapCodes(nil, mapping) != nil {
+		t.Errorf("expected nil")
+	}
+}
@@ -0,0 +1,102 @@
+package e
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// Promoter applies rules like Promote, caching which Rule matched for each
+// distinct foreign error in an LRU keyed by the error's type and message. This
+// makes classification cheap when the same driver error is seen millions of
+// times, e.g. during an outage, since errors.Is and errors.As are only
+// evaluated once per distinct error.
+//
+// Errors with the same type and message are assumed to match the same Rule,
+// which holds unless rules inspect fields which are not part of the message.
+//
+// A Promoter must be created with NewPromoter and is safe for concurrent use.
+type Promoter struct {
+	rules []Rule
+	size  int
+
+	mu      sync.Mutex
+	order   *list.List // of *promoterEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type promoterEntry struct {
+	key  string
+	rule int
+}
+
+// NewPromoter returns a Promoter which remembers the matching Rule of up to
+// size distinct errors. Invalid rules are reported as contract violations
+// once, here, instead of on every call to Promote.
+//
+// Usage:
+//
+//	var dbPromoter = e.NewPromoter(1024,
+//		e.Rule{Is: sql.ErrNoRows, Code: "not_exists"},
+//		e.Rule{As: new(*pq.Error), Code: "database_error"},
+//	)
+//
+//	func GetBar(id string) error {
+//		err := db.QueryRow(...).Scan(...)
+//		if err != nil {
+//			return dbPromoter.Promote(err)
+//		}
+//		return nil
+//	}
+func NewPromoter(size int, rules ...Rule) *Promoter {
+	if size < 1 {
+		size = 1
+	}
+	validateRules(loadConfig().callingOp(2), rules)
+	return &Promoter{
+		rules:   rules,
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Promote wraps err like Wrap and applies the code, message and retryability
+// of the first matching Rule, like the package-level Promote.
+func (p *Promoter) Promote(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	return wrap(err, 3).promote(p.match(err), p.rules)
+}
+
+// match returns the index of the first Rule matching err, or -1, from the
+// cache if possible.
+func (p *Promoter) match(err error) int {
+	key := fmt.Sprintf("%T\x00%s", err, err.Error())
+
+	p.mu.Lock()
+	if elem, ok := p.entries[key]; ok {
+		p.order.MoveToFront(elem)
+		rule := elem.Value.(*promoterEntry).rule
+		p.mu.Unlock()
+		return rule
+	}
+	p.mu.Unlock()
+
+	rule := matchRule(err, p.rules)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.entries[key]; ok {
+		return rule
+	}
+	p.entries[key] = p.order.PushFront(&promoterEntry{key: key, rule: rule})
+	if p.order.Len() > p.size {
+		oldest := p.order.Back()
+		p.order.Remove(oldest)
+		delete(p.entries, oldest.Value.(*promoterEntry).key)
+	}
+	return rule
+}
@@ -0,0 +1,57 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestPromoter(t *testing.T) {
+	p := NewPromoter(2, testRules...)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode Code
+	}{
+		{name: "matches Is rule", err: fmt.Errorf("query: %w", errNoRows), wantCode: "not_exists"},
+		{name: "cached Is rule", err: fmt.Errorf("query: %w", errNoRows), wantCode: "not_exists"},
+		{name: "matches As rule", err: &os.PathError{Op: "open", Path: "/tmp", Err: os.ErrNotExist}, wantCode: CodeInternal},
+		{name: "no match only wraps", err: errors.New("basic error"), wantCode: ""},
+		{name: "evicted entry is matched again", err: fmt.Errorf("query: %w", errNoRows), wantCode: "not_exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Promote(tt.err)
+			if got := ErrorCode(err); got != tt.wantCode {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.wantCode)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected promoted error to wrap original")
+			}
+		})
+	}
+
+	if p.order.Len() != 2 || len(p.entries) != 2 {
+		t.Errorf("expected cache to be capped at 2 entries but got %d", len(p.entries))
+	}
+	if p.Promote(nil) != nil {
+		t.Errorf("expected nil")
+	}
+}
+
+func BenchmarkPromoter(b *testing.B) {
+	err := fmt.Errorf("query: %w", errNoRows)
+	b.Run("Promote", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			Promote(err, testRules...)
+		}
+	})
+	b.Run("Promoter", func(b *testing.B) {
+		p := NewPromoter(1024, testRules...)
+		for n := 0; n < b.N; n++ {
+			p.Promote(err)
+		}
+	})
+}
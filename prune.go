@@ -0,0 +1,157 @@
+package e
+
+// Hop describes an Error in a chain for Prune.
+type Hop struct {
+	Op      string
+	Code    Code
+	Message string
+}
+
+// Prune returns err without the Errors in its chain for which prune returns
+// true, e.g. layers of generated code or middleware, so that Error() and
+// ErrorOps focus on meaningful application layers. Everything else a removed
+// Error carried, such as its code, message, fields or error ID, is moved to
+// the nearest kept Error outside it unless that Error sets its own, so codes and
+// messages returned to clients do not change.
+//
+// Only the Errors wrapping err down to the first error not created by this
+// package can be removed; deeper errors are kept as they are. An err not
+// created by this package is wrapped like Wrap.
+//
+// Usage:
+//
+//	err = e.Prune(err, func(hop e.Hop) bool {
+//		return strings.HasPrefix(hop.Op, "(*grpcGateway)") || strings.HasSuffix(hop.Op, "Middleware")
+//	})
+func Prune(err error, prune func(hop Hop) bool) Error {
+	if err == nil {
+		return nil
+	}
+
+	var hops []errorImpl
+	cur := err
+	for {
+		e, ok := cur.(errorImpl)
+		if !ok {
+			break
+		}
+		hops = append(hops, e)
+		cur = e.err
+	}
+	if len(hops) == 0 {
+		return wrap(err, 3)
+	}
+
+	// Rebuild the chain from the innermost Error outwards, carrying the
+	// contributions of removed Errors to the next kept one.
+	var pending *errorImpl
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := hops[i]
+		if prune(Hop{Op: hop.op, Code: hop.code, Message: hop.message}) {
+			if pending != nil {
+				hop = hop.absorb(*pending)
+			}
+			pending = &hop
+			continue
+		}
+		if pending != nil {
+			hop = hop.absorb(*pending)
+			pending = nil
+		}
+		hop.err = cur
+		cur = hop.withText()
+	}
+
+	if pending == nil {
+		return cur.(errorImpl)
+	}
+	// The outermost Errors were removed. Their contributions take precedence
+	// over those of the Error they wrapped, like they did before.
+	top := *pending
+	if inner, ok := cur.(errorImpl); ok {
+		top = top.absorb(inner)
+		top.op = inner.op
+		top.meta = inner.meta
+		top.err = inner.err
+		return top.withText()
+	}
+	top.op = ""
+	top.meta = nil
+	top.err = cur
+	return top.withText()
+}
+
+// absorb returns e with every property it does not set itself taken from
+// inner, except for the op, meta and wrapped error which describe the hop.
+// Fields and values are merged, with those of e taking precedence.
+func (e errorImpl) absorb(inner errorImpl) errorImpl {
+	if e.code == "" {
+		e.code = inner.code
+	}
+	if e.message == "" {
+		e.message = inner.message
+	}
+	if e.suggestion == "" {
+		e.suggestion = inner.suggestion
+	}
+	if e.details == nil {
+		e.details = inner.details
+	}
+	if e.groupKey == "" {
+		e.groupKey = inner.groupKey
+	}
+	if e.helpURL == "" {
+		e.helpURL = inner.helpURL
+	}
+	if e.parent == nil {
+		e.parent = inner.parent
+	}
+	if e.stack == nil {
+		e.stack = inner.stack
+	}
+	e.noStackExport = e.noStackExport || inner.noStackExport
+	if e.retryable == nil {
+		e.retryable = inner.retryable
+	}
+	if e.retryAfter == 0 {
+		e.retryAfter = inner.retryAfter
+	}
+	if e.id == "" {
+		e.id = inner.id
+	}
+	if e.created.IsZero() {
+		e.created = inner.created
+	}
+	if e.buildInfo == nil {
+		e.buildInfo = inner.buildInfo
+	}
+	if e.config == nil {
+		e.config = inner.config
+	}
+
+	if inner.fields != nil {
+		merged := make(fieldMap, len(*inner.fields))
+		for key, value := range *inner.fields {
+			merged[key] = value
+		}
+		if e.fields != nil {
+			for key, value := range *e.fields {
+				merged[key] = value
+			}
+		}
+		e.fields = &merged
+	}
+	if inner.values != nil {
+		merged := make(valueMap, len(*inner.values))
+		for key, value := range *inner.values {
+			merged[key] = value
+		}
+		if e.values != nil {
+			for key, value := range *e.values {
+				merged[key] = value
+			}
+		}
+		e.values = &merged
+	}
+	return e
+}
@@ -0,0 +1,97 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func middlewareWrap(err error) error {
+	return Wrap(err).SetMessage("Try again.").SetField("route", "/bars")
+}
+
+func TestPrune(t *testing.T) {
+	skipNoCapture(t)
+
+	err := Wrap(middlewareWrap(Foo())).SetID("abc")
+	opIs := func(ops ...string) func(Hop) bool {
+		return func(hop Hop) bool {
+			for _, op := range ops {
+				if hop.Op == op {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		prune    func(Hop) bool
+		wantOps  []string
+		wantText string
+	}{
+		{
+			name:     "middle hop",
+			err:      err,
+			prune:    opIs("middlewareWrap"),
+			wantOps:  []string{"TestPrune", "Foo"},
+			wantText: "TestPrune: Foo: [database_error] cannot foo",
+		},
+		{
+			name:     "outermost hop",
+			err:      err,
+			prune:    opIs("TestPrune"),
+			wantOps:  []string{"middlewareWrap", "Foo"},
+			wantText: "middlewareWrap: Foo: [database_error] cannot foo",
+		},
+		{
+			name:     "innermost hop",
+			err:      err,
+			prune:    opIs("Foo"),
+			wantOps:  []string{"TestPrune", "middlewareWrap"},
+			wantText: "TestPrune: middlewareWrap: [database_error] cannot foo",
+		},
+		{
+			name:     "every hop",
+			err:      err,
+			prune:    opIs("TestPrune", "middlewareWrap", "Foo"),
+			wantText: "[database_error] cannot foo",
+		},
+		{
+			name:     "no hop",
+			err:      err,
+			prune:    opIs(),
+			wantOps:  []string{"TestPrune", "middlewareWrap", "Foo"},
+			wantText: "TestPrune: middlewareWrap: Foo: [database_error] cannot foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pruned := Prune(tt.err, tt.prune)
+			if got := ErrorOps(pruned); !reflect.DeepEqual(got, tt.wantOps) {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.wantOps)
+			}
+			if got := pruned.Error(); got != tt.wantText {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.wantText)
+			}
+			got := []interface{}{ErrorCode(pruned), ErrorMessage(pruned), ErrorID(pruned), ErrorFields(pruned)["route"], ErrorStacktrace(pruned)}
+			want := []interface{}{ErrorCode(tt.err), ErrorMessage(tt.err), ErrorID(tt.err), "/bars", ErrorStacktrace(tt.err)}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected contributions to be preserved\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+
+	if got := Prune(nil, opIs()); got != nil {
+		t.Errorf("expected nil but got %v", got)
+	}
+	if got := ErrorOps(Prune(errors.New("basic error"), opIs())); !reflect.DeepEqual(got, []string{"TestPrune"}) {
+		t.Errorf("expected non-pkg error to be wrapped but got ops %v", got)
+	}
+	if got := ErrorOps(Prune(Wrap(fmt.Errorf("fmt: %w", Bar())), opIs("Bar"))); !reflect.DeepEqual(got, []string{"TestPrune", "Bar", "Foo"}) {
+		t.Errorf("expected errors below non-pkg error to be kept but got ops %v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package e
+
+import (
+	"encoding/hex"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+)
+
+// recentCapacity is the number of events kept for Recent.
+const recentCapacity = 256
+
+// Event describes an Error at the time it started a new error stack.
+type Event struct {
+	ID          string    `json:"id,omitempty"`
+	Code        string    `json:"code,omitempty"`
+	Op          string    `json:"op,omitempty"`
+	Time        time.Time `json:"time"`
+	Fingerprint string    `json:"fingerprint"`
+
+	// BuildInfo is the build which created the Error, if stamping is enabled
+	// with SetBuildInfo.
+	BuildInfo *BuildInfo `json:"build_info,omitempty"`
+}
+
+// recentEvent is an Event with the sequence number it was recorded with, so
+// readers can detect slots which were overwritten while they were reading.
+type recentEvent struct {
+	seq uint64
+	Event
+}
+
+var (
+	recentNext  uint64
+	recentSlots [recentCapacity]atomic.Value // *recentEvent
+)
+
+// recordRecent adds e, which starts a new error stack, to the events reported
+// by Recent.
+func recordRecent(e errorImpl) {
+	if smallFootprint {
+		return
+	}
+	seq := atomic.AddUint64(&recentNext, 1) - 1
+	recentSlots[seq%recentCapacity].Store(&recentEvent{
+		seq: seq,
+		Event: Event{
+			ID:          e.id,
+			Code:        string(e.code),
+			Op:          e.op,
+			Time:        e.created,
+			Fingerprint: Fingerprint(e),
+			BuildInfo:   e.buildInfo,
+		},
+	})
+}
+
+// Recent returns up to the last n errors which started a new error stack, most
+// recent first, so health endpoints and debugging sessions can inspect recent
+// failures without a log sink. At most 256 errors are kept. Errors are recorded
+// as constructed, before any SetX calls. Quiet codes are not recorded.
+//
+// Recording is lock-free. Events recorded concurrently with Recent may be
+// missing from its result. Nothing is recorded in builds with the e_small tag.
+//
+// Usage:
+//
+//	http.HandleFunc("/debug/errors", func(w http.ResponseWriter, r *http.Request) {
+//		json.NewEncoder(w).Encode(e.Recent(50))
+//	})
+func Recent(n int) []Event {
+	next := atomic.LoadUint64(&recentNext)
+	if n > recentCapacity {
+		n = recentCapacity
+	}
+	if uint64(n) > next {
+		n = int(next)
+	}
+
+	events := make([]Event, 0, n)
+	for i := uint64(1); i <= uint64(n); i++ {
+		seq := next - i
+		ev, ok := recentSlots[seq%recentCapacity].Load().(*recentEvent)
+		if !ok || ev.seq != seq {
+			continue
+		}
+		events = append(events, ev.Event)
+	}
+	return events
+}
+
+// Fingerprint returns a short hash identifying the kind of err, for grouping
+// occurrences of the same failure: the group key set with SetGroupKey if any,
+// otherwise the outermost code and the ops of the chain. Unlike the error text,
+// it does not vary with IDs or other values formatted into causes.
+func Fingerprint(err error) string {
+	h := fnv.New64a()
+	if key := ErrorGroupKey(err); key != "" {
+		h.Write([]byte("group\x00"))
+		h.Write([]byte(key))
+	} else {
+		h.Write([]byte(rawCode(err)))
+		for _, op := range ErrorOps(err) {
+			h.Write([]byte{0})
+			h.Write([]byte(op))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
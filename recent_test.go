@@ -0,0 +1,74 @@
+package e
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRecent(t *testing.T) {
+	skipNoCapture(t)
+	skipSmallFootprint(t)
+
+	for i := 0; i < recentCapacity; i++ {
+		NewErrorf(CodeInternal, "old %d", i)
+	}
+	first := NewError(CodeDatabase, "first")
+	second := NewError(CodeUnexpected, "second")
+
+	events := Recent(2)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events but got %v", events)
+	}
+	for i, err := range []Error{second, first} {
+		got := events[i]
+		if got.ID != ErrorID(err) || got.Code != string(rawCode(err)) || got.Op != "TestRecent" ||
+			!got.Time.Equal(ErrorTime(err)) || got.Fingerprint != Fingerprint(err) {
+			t.Errorf("unexpected event %+v for %v", got, err)
+		}
+	}
+	if got := len(Recent(recentCapacity + 10)); got != recentCapacity {
+		t.Errorf("expected Recent to be capped at %d but got %d", recentCapacity, got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	skipNoCapture(t)
+
+	tests := []struct {
+		name string
+		a, b error
+		same bool
+	}{
+		{
+			name: "ignores formatted values",
+			a:    NewErrorf(CodeDatabase, "id: %d", 1),
+			b:    NewErrorf(CodeDatabase, "id: %d", 2),
+			same: true,
+		},
+		{
+			name: "differs by code",
+			a:    NewError(CodeDatabase, "cannot foo"),
+			b:    NewError(CodeInternal, "cannot foo"),
+			same: false,
+		},
+		{
+			name: "differs by ops",
+			a:    Foo(),
+			b:    Bar(),
+			same: false,
+		},
+		{
+			name: "honors group key",
+			a:    Wrap(Foo()).SetGroupKey("tenant_config"),
+			b:    Wrap(fmt.Errorf("fmt: %w", Bar())).SetGroupKey("tenant_config"),
+			same: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fingerprint(tt.a) == Fingerprint(tt.b); got != tt.same {
+				t.Errorf("expected same fingerprint to be %v for %v and %v", tt.same, tt.a, tt.b)
+			}
+		})
+	}
+}
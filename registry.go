@@ -0,0 +1,360 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Severity classifies how serious errors with a code are.
+type Severity int
+
+// Severities in increasing order of seriousness.
+const (
+	SeverityUnknown Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// Descriptor describes everything known about a code in one place, so HTTP,
+// gRPC and other adapters never disagree about its mapping.
+type Descriptor struct {
+	Code Code
+
+	// HTTPStatus is the status code used when the error crosses an HTTP
+	// boundary, e.g. http.StatusNotFound.
+	HTTPStatus int
+
+	// GRPCCode is the canonical gRPC status code as a google.golang.org/grpc/codes.Code
+	// value. It is a uint32 so that this package does not depend on gRPC.
+	GRPCCode uint32
+
+	Severity Severity
+
+	// Retryable is inherited from the nearest ancestor which sets it, unless
+	// the code is registered with WithRetryable(false).
+	Retryable bool
+
+	// notRetryable is set by WithRetryable(false), so that the code is not
+	// retryable even under a retryable ancestor.
+	notRetryable bool
+
+	// HelpURL links to a runbook or documentation page for the code. See
+	// ErrorHelpURL.
+	HelpURL string
+
+	// Message describes the code for catalogs and API documentation, e.g.
+	// "The requested resource does not exist". See WriteCatalog.
+	Message string
+
+	// Owner is the team responsible for errors with the code.
+	Owner string
+}
+
+// Register adds d to the registry, replacing any previous Descriptor for the
+// same code.
+//
+// Usage:
+//
+//	func init() {
+//		e.Register(e.Descriptor{
+//			Code:       "not_exists",
+//			HTTPStatus: http.StatusNotFound,
+//			GRPCCode:   uint32(codes.NotFound),
+//			Severity:   e.SeverityInfo,
+//		})
+//	}
+func Register(d Descriptor) {
+	updateDescriptor(d.Code, func(registered *Descriptor) {
+		*registered = d
+	})
+}
+
+// updateDescriptor registers the Descriptor for code modified by fn. fn receives
+// the Descriptor registered for code itself, or an empty one.
+func updateDescriptor(code Code, fn func(*Descriptor)) {
+	updateConfig(func(c *Config) error {
+		registry := make(map[Code]Descriptor, len(c.registry)+1)
+		for other, d := range c.registry {
+			registry[other] = d
+		}
+		d, ok := registry[code]
+		if !ok {
+			d = Descriptor{Code: code}
+		}
+		fn(&d)
+		registry[code] = d
+		c.registry = registry
+		return nil
+	})
+}
+
+// statusInternalServerError is http.StatusInternalServerError, without
+// depending on net/http.
+const statusInternalServerError = 500
+
+// RegisterHTTPStatus sets the HTTPStatus of the Descriptor registered for code,
+// registering one if needed.
+//
+// Usage:
+//
+//	func init() {
+//		e.RegisterHTTPStatus("not_exists", http.StatusNotFound)
+//		e.RegisterHTTPStatus("validation_error", http.StatusBadRequest)
+//	}
+func RegisterHTTPStatus(code Code, status int) {
+	updateDescriptor(code, func(d *Descriptor) {
+		d.HTTPStatus = status
+	})
+}
+
+// HTTPStatus returns the HTTPStatus registered for the outermost code of err,
+// or 500 if there is none. Returns 200 for a nil error.
+//
+// Usage:
+//
+//	if err != nil {
+//		http.Error(w, e.ErrorMessage(err), e.HTTPStatus(err))
+//		return
+//	}
+func HTTPStatus(err error) int {
+	if err == nil {
+		return 200
+	}
+	if d, _ := Describe(rawCode(err)); d.HTTPStatus != 0 {
+		return d.HTTPStatus
+	}
+	return statusInternalServerError
+}
+
+// CodeOption sets a property of the Descriptor registered by RegisterCode.
+type CodeOption func(*Descriptor)
+
+// WithHTTPStatus sets Descriptor.HTTPStatus.
+func WithHTTPStatus(status int) CodeOption {
+	return func(d *Descriptor) { d.HTTPStatus = status }
+}
+
+// WithGRPCCode sets Descriptor.GRPCCode.
+func WithGRPCCode(code uint32) CodeOption {
+	return func(d *Descriptor) { d.GRPCCode = code }
+}
+
+// WithSeverity sets Descriptor.Severity.
+func WithSeverity(severity Severity) CodeOption {
+	return func(d *Descriptor) { d.Severity = severity }
+}
+
+// WithRetryable sets Descriptor.Retryable. Unlike a Descriptor registered
+// without Retryable, WithRetryable(false) overrides a retryable ancestor.
+func WithRetryable(retryable bool) CodeOption {
+	return func(d *Descriptor) {
+		d.Retryable = retryable
+		d.notRetryable = !retryable
+	}
+}
+
+// WithHelpURL sets Descriptor.HelpURL.
+func WithHelpURL(url string) CodeOption {
+	return func(d *Descriptor) { d.HelpURL = url }
+}
+
+// WithMessage sets Descriptor.Message.
+func WithMessage(message string) CodeOption {
+	return func(d *Descriptor) { d.Message = message }
+}
+
+// WithOwner sets Descriptor.Owner.
+func WithOwner(owner string) CodeOption {
+	return func(d *Descriptor) { d.Owner = owner }
+}
+
+// RegisterCode declares code and registers a Descriptor for it built from opts,
+// replacing any previous Descriptor for code. It returns code so that codes can
+// be declared and registered in one place.
+//
+// Usage:
+//
+//	var CodeNotExists = e.RegisterCode("not_exists",
+//		e.WithHTTPStatus(http.StatusNotFound),
+//		e.WithSeverity(e.SeverityInfo),
+//	)
+func RegisterCode(code Code, opts ...CodeOption) Code {
+	d := Descriptor{Code: code}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	Register(d)
+	return code
+}
+
+// Describe returns the registered Descriptor for code. For hierarchical codes
+// such as "database.connection.timeout", each property which code itself does
+// not set is taken from the nearest registered ancestor which does
+// ("database.connection", then "database"). Descriptor.Code is the nearest
+// registered code.
+func Describe(code Code) (Descriptor, bool) {
+	return loadConfig().describe(code)
+}
+
+func (c *Config) describe(code Code) (Descriptor, bool) {
+	var (
+		d     Descriptor
+		found bool
+	)
+	for ; code != ""; code = ParentCode(code) {
+		registered, ok := c.registry[code]
+		if !ok {
+			continue
+		}
+		if !found {
+			d.Code = registered.Code
+			found = true
+		}
+		d.inherit(registered)
+	}
+	return d, found
+}
+
+// inherit sets every property of d which is not set from parent.
+func (d *Descriptor) inherit(parent Descriptor) {
+	if d.HTTPStatus == 0 {
+		d.HTTPStatus = parent.HTTPStatus
+	}
+	if d.GRPCCode == 0 {
+		d.GRPCCode = parent.GRPCCode
+	}
+	if d.Severity == SeverityUnknown {
+		d.Severity = parent.Severity
+	}
+	if !d.Retryable && !d.notRetryable {
+		d.Retryable = parent.Retryable
+		d.notRetryable = parent.notRetryable
+	}
+	if d.HelpURL == "" {
+		d.HelpURL = parent.HelpURL
+	}
+	if d.Message == "" {
+		d.Message = parent.Message
+	}
+	if d.Owner == "" {
+		d.Owner = parent.Owner
+	}
+}
+
+// Strictness controls what happens when an Error is created with, or set to, a
+// code which was not registered. Codes assigned by this package are always
+// considered registered.
+type Strictness int
+
+const (
+	// StrictnessOff accepts any code. This is the default.
+	StrictnessOff Strictness = iota
+
+	// StrictnessReport reports unregistered codes as contract violations,
+	// which are passed to hooks or panic when SetDevMode is enabled.
+	StrictnessReport
+
+	// StrictnessPanic panics on unregistered codes.
+	StrictnessPanic
+)
+
+func (s Strictness) String() string {
+	switch s {
+	case StrictnessOff:
+		return "off"
+	case StrictnessReport:
+		return "report"
+	case StrictnessPanic:
+		return "panic"
+	}
+	return "unknown"
+}
+
+// SetCodeStrictness makes NewError, SetCode and the other constructors which
+// take a code validate it against the registry, so typos such as
+// "databse_error" are caught in development rather than on dashboards.
+//
+// Usage:
+//
+//	func TestMain(m *testing.M) {
+//		e.SetCodeStrictness(e.StrictnessPanic)
+//		os.Exit(m.Run())
+//	}
+func SetCodeStrictness(strictness Strictness) {
+	Configure(func(c *Config) {
+		c.CodeStrictness = strictness
+	})
+}
+
+// validateCode enforces the CodeStrictness of c for code set on an Error in op.
+func (c *Config) validateCode(op string, code Code) {
+	if c.CodeStrictness == StrictnessOff || code == "" || isBuiltinCode(code) {
+		return
+	}
+	if _, ok := c.describe(code); ok {
+		return
+	}
+
+	cause := fmt.Sprintf("unregistered code %q", code)
+	if c.CodeStrictness == StrictnessPanic {
+		panic(errorImpl{
+			op:   op,
+			code: CodeContractViolation,
+			err:  errors.New(cause),
+		})
+	}
+	reportViolation(op, cause)
+}
+
+// grpcUnknown is codes.Unknown, without depending on gRPC.
+const grpcUnknown = 2
+
+// RegisterGRPCCode sets the GRPCCode of the Descriptor registered for code,
+// registering one if needed. c is a google.golang.org/grpc/codes.Code value.
+//
+// Usage:
+//
+//	func init() {
+//		e.RegisterGRPCCode("not_exists", uint32(codes.NotFound))
+//	}
+func RegisterGRPCCode(code Code, c uint32) {
+	updateDescriptor(code, func(d *Descriptor) {
+		d.GRPCCode = c
+	})
+}
+
+// GRPCCode returns the GRPCCode registered for the outermost code of err, or
+// codes.Unknown if there is none. Returns codes.OK for a nil error. The result
+// is a uint32 so that this package does not depend on gRPC; convert it with
+// codes.Code.
+//
+// Usage:
+//
+//	if err != nil {
+//		return nil, status.Error(codes.Code(e.GRPCCode(err)), e.ErrorMessage(err))
+//	}
+func GRPCCode(err error) uint32 {
+	if err == nil {
+		return 0
+	}
+	if d, _ := Describe(rawCode(err)); d.GRPCCode != 0 {
+		return d.GRPCCode
+	}
+	return grpcUnknown
+}
@@ -0,0 +1,212 @@
+package e
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	want := Descriptor{
+		Code:       "test_not_exists",
+		HTTPStatus: 404,
+		GRPCCode:   5,
+		Severity:   SeverityInfo,
+	}
+	Register(want)
+
+	got, ok := Describe("test_not_exists")
+	if !ok || got != want {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	want.Retryable = true
+	Register(want)
+	if got, _ := Describe("test_not_exists"); !got.Retryable {
+		t.Errorf("expected Register to replace descriptor")
+	}
+
+	if got, ok := Describe("test_not_exists.tenant"); !ok || got.Code != "test_not_exists" {
+		t.Errorf("expected descriptor of parent code but got %+v", got)
+	}
+	if _, ok := Describe("unregistered"); ok {
+		t.Errorf("expected unregistered code not to be found")
+	}
+	if got := SeverityCritical.String(); got != "critical" {
+		t.Errorf("\ngot:  %q\nwant: %q", got, "critical")
+	}
+}
+
+func TestRegisterCode(t *testing.T) {
+	code := RegisterCode("test_quota_exceeded",
+		WithHTTPStatus(429),
+		WithGRPCCode(8),
+		WithSeverity(SeverityWarning),
+		WithRetryable(true),
+		WithHelpURL("https://example.com/quota"),
+	)
+	want := Descriptor{
+		Code:       "test_quota_exceeded",
+		HTTPStatus: 429,
+		GRPCCode:   8,
+		Severity:   SeverityWarning,
+		Retryable:  true,
+		HelpURL:    "https://example.com/quota",
+	}
+	if got, ok := Describe(code); !ok || got != want {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestDescribeInheritsRetryable(t *testing.T) {
+	RegisterCode("test_upstream", WithRetryable(true))
+	RegisterCode("test_upstream.timeout", WithHTTPStatus(504))
+	RegisterCode("test_upstream.rejected", WithRetryable(false))
+	RegisterCode("test_upstream.rejected.quota", WithHTTPStatus(429))
+
+	tests := []struct {
+		code Code
+		want bool
+	}{
+		{code: "test_upstream", want: true},
+		{code: "test_upstream.timeout", want: true},
+		{code: "test_upstream.rejected", want: false},
+		{code: "test_upstream.rejected.quota", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			if got, _ := Describe(tt.code); got.Retryable != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got.Retryable, tt.want)
+			}
+			if got := IsRetryable(NewError(tt.code, "cannot call upstream")); got != tt.want {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetCodeStrictness(t *testing.T) {
+	skipSmallFootprint(t)
+
+	RegisterCode("test_registered")
+	defer SetCodeStrictness(StrictnessOff)
+
+	var violations []string
+	remove := AddHook(func(err Error) {
+		if ErrorCode(err) == CodeContractViolation {
+			violations = append(violations, err.Error())
+		}
+	})
+	defer remove()
+
+	NewError("databse_error", "typo is accepted")
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations when off but got %v", violations)
+	}
+
+	SetCodeStrictness(StrictnessReport)
+	NewError("test_registered", "registered")
+	NewError(CodeValidation, "builtin")
+	NewError("", "no code")
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations for known codes but got %v", violations)
+	}
+	NewError("databse_error", "typo")
+	Wrap(errors.New("basic error")).SetCode("databse_error")
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations but got %v", violations)
+	}
+
+	SetCodeStrictness(StrictnessPanic)
+	defer func() {
+		if r := recover(); ErrorCode(r.(error)) != CodeContractViolation {
+			t.Errorf("expected contract violation panic but got %v", r)
+		}
+	}()
+	NewError("databse_error", "typo")
+	t.Errorf("expected panic")
+}
+
+func TestHTTPStatus(t *testing.T) {
+	RegisterCode("test_conflict", WithSeverity(SeverityInfo))
+	RegisterHTTPStatus("test_conflict", 409)
+	RegisterHTTPStatus("test_unauthorized", 401)
+
+	if d, _ := Describe("test_conflict"); d.Severity != SeverityInfo {
+		t.Errorf("expected RegisterHTTPStatus to keep other properties but got %+v", d)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil error", err: nil, want: 200},
+		{name: "registered code", err: Wrap(NewError("test_conflict", "already exists")), want: 409},
+		{name: "code registered without descriptor", err: NewError("test_unauthorized", "no token"), want: 401},
+		{name: "unregistered code", err: Foo(), want: 500},
+		{name: "non-pkg error", err: errors.New("basic error"), want: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPStatus(tt.err); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPCCode(t *testing.T) {
+	RegisterGRPCCode("test_grpc_not_found", 5)
+
+	tests := []struct {
+		name string
+		err  error
+		want uint32
+	}{
+		{name: "nil error", err: nil, want: 0},
+		{name: "registered code", err: Wrap(NewError("test_grpc_not_found", "no user")), want: 5},
+		{name: "registered parent code", err: NewError("test_grpc_not_found.tenant", "no tenant"), want: 5},
+		{name: "unregistered code", err: Foo(), want: 2},
+		{name: "non-pkg error", err: errors.New("basic error"), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GRPCCode(tt.err); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeInheritsFromAncestors(t *testing.T) {
+	RegisterCode("test_database",
+		WithSeverity(SeverityError),
+		WithHelpURL("https://example.com/database"),
+		WithOwner("storage"),
+	)
+	RegisterHTTPStatus("test_database", 503)
+	RegisterGRPCCode("test_database.timeout", 4)
+
+	want := Descriptor{
+		Code:       "test_database.timeout",
+		HTTPStatus: 503,
+		GRPCCode:   4,
+		Severity:   SeverityError,
+		HelpURL:    "https://example.com/database",
+		Owner:      "storage",
+	}
+	if got, ok := Describe("test_database.timeout.read"); !ok || got != want {
+		t.Errorf("\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	err := NewError("test_database.timeout", "cannot query")
+	if got := HTTPStatus(err); got != 503 {
+		t.Errorf("expected HTTP status of parent but got %d", got)
+	}
+	if got := GRPCCode(err); got != 4 {
+		t.Errorf("expected gRPC code of child but got %d", got)
+	}
+	if got := ErrorHelpURL(err); got != "https://example.com/database" {
+		t.Errorf("expected help URL of parent but got %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package e
+
+import (
+	"errors"
+	"time"
+)
+
+// HasRetryable allows custom error types to be used with utility function
+// IsRetryable().
+type HasRetryable interface {
+
+	// Retryable reports whether the operation which failed may succeed if it
+	// is attempted again.
+	//
+	// Note: IsRetryable() should be used to check the whole chain.
+	Retryable() bool
+}
+
+func (e errorImpl) SetRetryable(retryable bool) Error {
+	e.retryable = &retryable
+	return e
+}
+
+// IsRetryable reports whether the operation which returned err may succeed if
+// it is attempted again. The outermost Error marked with SetRetryable, or error
+// implementing HasRetryable, decides. Otherwise the Descriptor registered for
+// the code of err decides, and errors without one are not retryable.
+//
+// Usage:
+//
+//	for attempt := 0; ; attempt++ {
+//		err := client.Send(msg)
+//		if err == nil || !e.IsRetryable(err) || attempt == maxAttempts {
+//			return err
+//		}
+//		time.Sleep(backoff(attempt))
+//	}
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for inner := err; inner != nil; inner = errors.Unwrap(inner) {
+		switch e := inner.(type) {
+		case errorImpl:
+			if e.retryable != nil {
+				return *e.retryable
+			}
+		case HasRetryable:
+			return e.Retryable()
+		}
+	}
+
+	d, _ := Describe(rawCode(err))
+	return d.Retryable
+}
+
+// HasRetryAfter allows custom error types to be used with utility function
+// ErrorRetryAfter().
+type HasRetryAfter interface {
+
+	// RetryAfter returns how long to wait before retrying, or 0 if unknown.
+	//
+	// Note: ErrorRetryAfter() should be used to retrieve the topmost RetryAfter().
+	RetryAfter() time.Duration
+}
+
+func (e errorImpl) SetRetryAfter(d time.Duration) Error {
+	e.retryAfter = d
+	return e
+}
+
+func (e errorImpl) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// ErrorRetryAfter returns the first unwrapped positive RetryAfter() of an error
+// which implements HasRetryAfter interface. Otherwise returns 0.
+//
+// Usage:
+//
+//	if d := e.ErrorRetryAfter(err); d > 0 {
+//		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+//	}
+func ErrorRetryAfter(err error) time.Duration {
+	for err != nil {
+		if e, ok := err.(HasRetryAfter); ok && e.RetryAfter() > 0 {
+			return e.RetryAfter()
+		}
+		err = errors.Unwrap(err)
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary" }
+func (temporaryError) Retryable() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	Register(Descriptor{Code: "test_unavailable", Retryable: true})
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error is not retryable",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "unmarked error is not retryable",
+			err:  Foo(),
+			want: false,
+		},
+		{
+			name: "marked error is retryable",
+			err:  Wrap(Foo()).SetRetryable(true),
+			want: true,
+		},
+		{
+			name: "outermost mark wins",
+			err:  Wrap(fmt.Errorf("fmt: %w", Wrap(Foo()).SetRetryable(true))).SetRetryable(false),
+			want: false,
+		},
+		{
+			name: "honors HasRetryable",
+			err:  Wrap(temporaryError{}),
+			want: true,
+		},
+		{
+			name: "falls back to registered code",
+			err:  Wrap(NewError("test_unavailable", "try later")),
+			want: true,
+		},
+		{
+			name: "mark overrides registered code",
+			err:  NewError("test_unavailable", "try later").SetRetryable(false),
+			want: false,
+		},
+		{
+			name: "non-pkg error is not retryable",
+			err:  errors.New("basic error"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+	}{
+		{
+			name: "unset hint returns 0",
+			err:  Foo(),
+			want: 0,
+		},
+		{
+			name: "returns outermost hint",
+			err:  Wrap(Wrap(Foo()).SetRetryAfter(time.Second)).SetRetryAfter(time.Minute),
+			want: time.Minute,
+		},
+		{
+			name: "works with non-pkg wrapping",
+			err:  Wrap(fmt.Errorf("fmt: %w", Wrap(Foo()).SetRetryAfter(time.Second))),
+			want: time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorRetryAfter(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RowError describes an error recorded by RowErrors.
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error"`
+}
+
+// RowReport is the machine-readable report of RowErrors, e.g. for the response
+// of a bulk-upload endpoint.
+type RowReport struct {
+	// Failed is the number of errors recorded, including omitted ones.
+	Failed int `json:"failed"`
+
+	// Omitted is the number of errors which were counted but not retained.
+	Omitted int `json:"omitted,omitempty"`
+
+	Rows []RowError `json:"rows"`
+}
+
+// RowErrors collects per-row errors of an import, such as a CSV upload, so
+// that every bad row can be reported at once instead of failing on the first.
+// Only the first max errors are retained; later ones are counted.
+//
+// RowErrors is safe for concurrent use.
+type RowErrors struct {
+	mu     sync.Mutex
+	max    int
+	failed int
+	rows   []RowError
+}
+
+// NewRowErrors returns a RowErrors which retains at most max errors. max <= 0
+// retains every error.
+//
+// Usage:
+//
+//	rowErrs := e.NewRowErrors(100)
+//	for row := 1; ; row++ {
+//		record, err := r.Read()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err := validate(record); err != nil {
+//			rowErrs.Add(row, "email", err)
+//		}
+//	}
+//	if err := rowErrs.Err(); err != nil {
+//		return err // details hold rowErrs.Report()
+//	}
+func NewRowErrors(max int) *RowErrors {
+	return &RowErrors{max: max}
+}
+
+// Add records err for row. column may be empty if err is not specific to a
+// column. A nil err is ignored.
+func (r *RowErrors) Add(row int, column string, err error) {
+	if err == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failed++
+	if r.max > 0 && len(r.rows) >= r.max {
+		return
+	}
+	r.rows = append(r.rows, RowError{
+		Row:     row,
+		Column:  column,
+		Code:    string(ErrorCode(err)),
+		Message: ErrorMessage(err),
+		Error:   err.Error(),
+	})
+}
+
+// Len returns the number of errors recorded, including omitted ones.
+func (r *RowErrors) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failed
+}
+
+// Report returns the recorded errors in the order they were added.
+func (r *RowErrors) Report() RowReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rows := make([]RowError, len(r.rows))
+	copy(rows, r.rows)
+	return RowReport{
+		Failed:  r.failed,
+		Omitted: r.failed - len(r.rows),
+		Rows:    rows,
+	}
+}
+
+// Summary returns a human-readable summary of the recorded errors, e.g.
+// "2 rows failed: row 3 (email): invalid address; row 7: too many columns".
+func (r *RowErrors) Summary() string {
+	report := r.Report()
+
+	var sb strings.Builder
+	if report.Failed == 1 {
+		sb.WriteString("1 row failed")
+	} else {
+		sb.WriteString(fmt.Sprintf("%d rows failed", report.Failed))
+	}
+	for i, row := range report.Rows {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(fmt.Sprintf("row %d", row.Row))
+		if row.Column != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", row.Column))
+		}
+		sb.WriteString(": ")
+		if row.Message != "" {
+			sb.WriteString(row.Message)
+		} else {
+			sb.WriteString(row.Error)
+		}
+	}
+	if report.Omitted > 0 {
+		sb.WriteString(fmt.Sprintf("; and %d more", report.Omitted))
+	}
+	return sb.String()
+}
+
+// Err returns nil if no errors were recorded. Otherwise it returns an Error
+// with code CodeValidation, the Summary as its cause and message, and the
+// Report as its details.
+func (r *RowErrors) Err() Error {
+	if r.Len() == 0 {
+		return nil
+	}
+
+	summary := r.Summary()
+	return newError(3, CodeValidation, errors.New(summary)).
+		SetMessage(summary).
+		SetDetails(r.Report())
+}
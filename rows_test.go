@@ -0,0 +1,52 @@
+package e
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestRowErrors(t *testing.T) {
+	t.Run("no errors returns nil", func(t *testing.T) {
+		r := NewRowErrors(10)
+		r.Add(1, "email", nil)
+		if err := r.Err(); err != nil {
+			t.Errorf("expected nil but got %v", err)
+		}
+	})
+	t.Run("summarizes rows and caps retained errors", func(t *testing.T) {
+		r := NewRowErrors(2)
+		r.Add(3, "email", NewError(CodeValidation, "bad email").SetMessage("invalid address"))
+		r.Add(7, "", errors.New("too many columns"))
+		r.Add(9, "age", errors.New("not a number"))
+
+		want := "3 rows failed: row 3 (email): invalid address; row 7: too many columns; and 1 more"
+		if got := r.Summary(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+
+		err := r.Err()
+		if ErrorCode(err) != CodeValidation || ErrorMessage(err) != want {
+			t.Errorf("unexpected error %v", err)
+		}
+		report, ok := ErrorDetails(err).(RowReport)
+		if !ok || report.Failed != 3 || report.Omitted != 1 || len(report.Rows) != 2 {
+			t.Fatalf("unexpected report %+v", ErrorDetails(err))
+		}
+		if row := report.Rows[0]; row.Row != 3 || row.Column != "email" || row.Code != string(CodeValidation) {
+			t.Errorf("unexpected row %+v", row)
+		}
+	})
+	t.Run("report encodes to JSON", func(t *testing.T) {
+		r := NewRowErrors(0)
+		r.Add(2, "name", errors.New("required"))
+		b, err := json.Marshal(r.Report())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := `{"failed":1,"rows":[{"row":2,"column":"name","error":"required"}]}`
+		if string(b) != want {
+			t.Errorf("\ngot:  %s\nwant: %s", b, want)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package e
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// SetStackSampling makes new error stacks capture a stack for only 1 in every
+// n errors of the same code, starting with the first, so that high-volume
+// error paths do not pay for every stack while still getting representative
+// traces. Errors which were not sampled behave like errors created with
+// WithoutStack. Panics and contract violations always capture a stack.
+// n <= 1 captures every stack.
+//
+// Usage:
+//
+//	e.SetStackSampling(100)
+func SetStackSampling(n int) {
+	Configure(func(c *Config) {
+		c.StackSampling = n
+	})
+}
+
+// stackSamples counts new error stacks by code for SetStackSampling. Values
+// are *uint64.
+var stackSamples sync.Map
+
+// sampleStack reports whether a new error stack with code should capture a
+// stack according to the StackSampling of c.
+func (c *Config) sampleStack(code Code) bool {
+	if c.StackSampling <= 1 || code == CodePanic || code == CodeContractViolation {
+		return true
+	}
+	n, ok := stackSamples.Load(code)
+	if !ok {
+		n, _ = stackSamples.LoadOrStore(code, new(uint64))
+	}
+	return (atomic.AddUint64(n.(*uint64), 1)-1)%uint64(c.StackSampling) == 0
+}
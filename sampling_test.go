@@ -0,0 +1,31 @@
+package e
+
+import "testing"
+
+func TestSetStackSampling(t *testing.T) {
+	skipNoCapture(t)
+
+	SetStackSampling(3)
+	defer SetStackSampling(0)
+
+	var sampled []bool
+	for i := 0; i < 7; i++ {
+		err := Wrap(NewError("test_sampled", "cannot foo"))
+		sampled = append(sampled, ErrorStacktrace(err) != "")
+	}
+	want := []bool{true, false, false, true, false, false, true}
+	for i := range want {
+		if sampled[i] != want[i] {
+			t.Fatalf("\ngot:  %v\nwant: %v", sampled, want)
+		}
+	}
+
+	if ErrorStacktrace(NewError("test_sampled_other", "cannot bar")) == "" {
+		t.Errorf("expected first error of another code to be sampled")
+	}
+	for i := 0; i < 2; i++ {
+		if ErrorStacktrace(newPanicError("boom")) == "" {
+			t.Errorf("expected panics to always capture a stack")
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package e
+
+import (
+	"errors"
+	"sync"
+)
+
+// Singleflight coalesces concurrent calls with the same key into one call, so a
+// failing dependency produces one Error, and one captured stack, rather than
+// one per caller. Every caller of a coalesced call receives the same Error with
+// the KeyCoalescedCallers field set to the number of callers which shared it.
+//
+// The zero Singleflight is ready to use and is safe for concurrent use.
+type Singleflight struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is a call in progress or completed.
+type flight struct {
+	wg      sync.WaitGroup
+	callers int
+	val     interface{}
+	err     error
+}
+
+// Do calls fn and returns its results, unless a call for key is already in
+// progress, in which case it waits for that call and returns the same results.
+// Errors which are not already an Error are wrapped.
+//
+// If fn panics, the panic is re-raised in the caller which made the call,
+// while the callers which waited for it receive an Error with code CodePanic.
+//
+// Usage:
+//
+//	v, err := configFlight.Do(tenantID, func() (interface{}, error) {
+//		return loadTenantConfig(ctx, tenantID)
+//	})
+//	if err != nil {
+//		return e.Wrap(err)
+//	}
+//	cfg := v.(*TenantConfig)
+func (g *Singleflight) Do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flight)
+	}
+	if f, ok := g.calls[key]; ok {
+		f.callers++
+		g.mu.Unlock()
+		f.wg.Wait()
+		return f.val, f.err
+	}
+	f := &flight{callers: 1}
+	f.wg.Add(1)
+	g.calls[key] = f
+	g.mu.Unlock()
+	// Deferred so that waiters are released even if fn panics or exits the
+	// goroutine.
+	defer f.wg.Done()
+
+	recovered, panicked := g.call(key, f, fn)
+
+	g.mu.Lock()
+	callers := f.callers
+	g.mu.Unlock()
+
+	if f.err != nil {
+		wrapped, ok := f.err.(Error)
+		if !ok {
+			wrapped = wrap(f.err, 3)
+		}
+		f.err = wrapped.SetField(KeyCoalescedCallers, callers)
+	}
+	if panicked {
+		panic(recovered)
+	}
+	return f.val, f.err
+}
+
+// call runs fn for f and removes f from g once fn is done, whether it returned
+// or not. A panic of fn is recovered, recorded as the error of f for waiters,
+// and returned to be re-raised.
+func (g *Singleflight) call(key string, f *flight, fn func() (interface{}, error)) (recovered interface{}, panicked bool) {
+	returned := false
+	defer func() {
+		if !returned {
+			if recovered = recover(); recovered != nil {
+				panicked = true
+				f.err = newPanicError(recovered)
+			} else {
+				f.err = errors.New("singleflight call exited without returning")
+			}
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+	}()
+
+	f.val, f.err = fn()
+	returned = true
+	return nil, false
+}
@@ -0,0 +1,102 @@
+package e
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSingleflight(t *testing.T) {
+	t.Run("returns results", func(t *testing.T) {
+		var g Singleflight
+		v, err := g.Do("key", func() (interface{}, error) { return 42, nil })
+		if v != 42 || err != nil {
+			t.Errorf("unexpected results %v, %v", v, err)
+		}
+	})
+	t.Run("coalesces concurrent failing calls", func(t *testing.T) {
+		var g Singleflight
+		const callers = 5
+
+		var (
+			release = make(chan struct{})
+			wg      sync.WaitGroup
+			calls   int
+			errs    = make([]error, callers)
+		)
+		fn := func() (interface{}, error) {
+			calls++
+			<-release
+			return nil, errors.New("upstream unavailable")
+		}
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = g.Do("key", fn)
+			}(i)
+		}
+		// Release the call once every caller has joined it.
+		for joined := 0; joined < callers; {
+			g.mu.Lock()
+			if f := g.calls["key"]; f != nil {
+				joined = f.callers
+			}
+			g.mu.Unlock()
+			runtime.Gosched()
+		}
+		close(release)
+		wg.Wait()
+
+		if calls != 1 {
+			t.Errorf("expected 1 call but got %d", calls)
+		}
+		for _, err := range errs {
+			if err != errs[0] {
+				t.Fatalf("expected every caller to share one error but got %v and %v", err, errs[0])
+			}
+		}
+		if got := ErrorFields(errs[0])[KeyCoalescedCallers]; got != callers {
+			t.Errorf("expected %d coalesced callers but got %v", callers, got)
+		}
+	})
+}
+
+func TestSingleflightPanic(t *testing.T) {
+	var g Singleflight
+	var (
+		release = make(chan struct{})
+		waited  = make(chan error)
+	)
+	go func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected panic to be re-raised in the caller but got %v", r)
+			}
+			close(release)
+		}()
+		g.Do("key", func() (interface{}, error) {
+			go func() {
+				_, err := g.Do("key", func() (interface{}, error) { return nil, nil })
+				waited <- err
+			}()
+			// Panic once the second caller has joined the call.
+			for joined := 0; joined < 2; {
+				g.mu.Lock()
+				joined = g.calls["key"].callers
+				g.mu.Unlock()
+				runtime.Gosched()
+			}
+			panic("boom")
+		})
+	}()
+
+	if err := <-waited; !IsCode(err, CodePanic) {
+		t.Errorf("expected waiting caller to receive a panic error but got %v", err)
+	}
+	<-release
+	if v, err := g.Do("key", func() (interface{}, error) { return 42, nil }); v != 42 || err != nil {
+		t.Errorf("expected key to be usable after a panic but got %v, %v", v, err)
+	}
+}
@@ -0,0 +1,142 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// maxSizeDepth bounds how deeply SizeOf follows pointers and containers in
+// fields, values and details, which also guards against cycles.
+const maxSizeDepth = 8
+
+// SetMaxErrorSize makes SetField, SetFields, SetDetails and WithValue report a
+// contract violation when the SizeOf of the resulting Error exceeds max bytes,
+// catching accidental attachment of huge payloads such as entire request
+// bodies to errors which are held in queues. Violations are passed to hooks as
+// an Error with code CodeContractViolation, or panic when SetDevMode is
+// enabled. max <= 0 disables the check, as do builds with the e_small tag.
+func SetMaxErrorSize(max int) {
+	Configure(func(c *Config) {
+		c.MaxErrorSize = max
+	})
+}
+
+// checkSize reports a violation if e exceeds the configured MaxErrorSize.
+func checkSize(e errorImpl) {
+	max := loadConfig().MaxErrorSize
+	if max <= 0 || smallFootprint {
+		return
+	}
+	if size := SizeOf(e); size > max {
+		reportViolation(e.op, fmt.Sprintf("error size of %d bytes exceeds maximum of %d bytes", size, max))
+	}
+}
+
+// SizeOf estimates the memory retained by the chain of err in bytes: strings,
+// stacks, fields, values and details of every Error, and the text of other
+// errors. Shared stacks are counted once. It is an estimate meant for catching
+// outliers rather than exact accounting.
+func SizeOf(err error) int {
+	size := 0
+	stacks := make(map[*stack]bool)
+	Walk(err, func(err error) bool {
+		e, ok := err.(errorImpl)
+		if !ok {
+			size += foreignSize(err)
+			return true
+		}
+
+		size += int(unsafe.Sizeof(e)) +
+			len(e.op) + len(e.code) + len(e.message) + len(e.suggestion) +
+			len(e.helpURL) + len(e.groupKey) + len(e.id)
+		if e.stack != nil && !stacks[e.stack] {
+			stacks[e.stack] = true
+			size += len(e.stack.pcs) * int(unsafe.Sizeof(uintptr(0)))
+		}
+		if e.fields != nil {
+			for key, value := range *e.fields {
+				size += len(key) + valueSize(reflect.ValueOf(value), 0)
+			}
+		}
+		if e.values != nil {
+			for _, value := range *e.values {
+				size += valueSize(reflect.ValueOf(value), 0)
+			}
+		}
+		if e.details != nil {
+			size += valueSize(reflect.ValueOf(e.details.v), 0)
+		}
+		if e.meta != nil {
+			size += len(e.meta.Component) + len(e.meta.Table)
+		}
+		return true
+	})
+	return size
+}
+
+// foreignSize estimates the size of an error not created by this package by
+// the text it adds to the text of the error it wraps.
+func foreignSize(err error) int {
+	size := len(err.Error())
+	if inner := errors.Unwrap(err); inner != nil {
+		size -= len(inner.Error())
+	}
+	if size < 0 {
+		return 0
+	}
+	return size
+}
+
+// valueSize estimates the memory retained by v.
+func valueSize(v reflect.Value, depth int) int {
+	if !v.IsValid() || depth > maxSizeDepth {
+		return 0
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.Len()
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return int(v.Type().Size()) + valueSize(v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		elem := v.Type().Elem()
+		if isFlat(elem) {
+			return v.Len() * int(elem.Size())
+		}
+		size := 0
+		for i := 0; i < v.Len(); i++ {
+			size += valueSize(v.Index(i), depth+1)
+		}
+		return size
+	case reflect.Map:
+		size := 0
+		iter := v.MapRange()
+		for iter.Next() {
+			size += valueSize(iter.Key(), depth+1) + valueSize(iter.Value(), depth+1)
+		}
+		return size
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < v.NumField(); i++ {
+			size += valueSize(v.Field(i), depth+1)
+		}
+		return size
+	}
+	return int(v.Type().Size())
+}
+
+// isFlat reports whether values of t retain no memory beyond their own size.
+func isFlat(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
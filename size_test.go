@@ -0,0 +1,72 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestSizeOf(t *testing.T) {
+	skipNoCapture(t)
+
+	if got := SizeOf(nil); got != 0 {
+		t.Errorf("expected 0 for nil but got %d", got)
+	}
+	if got := SizeOf(errors.New("basic error")); got != len("basic error") {
+		t.Errorf("expected size of text but got %d", got)
+	}
+
+	err := Foo()
+	base := SizeOf(err)
+	stackSize := len(err.(errorImpl).stack.pcs) * int(unsafe.Sizeof(uintptr(0)))
+	if base < stackSize {
+		t.Errorf("expected size to include stacktrace but got %d", base)
+	}
+	if got := SizeOf(Wrap(err)); got-base >= int(unsafe.Sizeof(errorImpl{}))+stackSize {
+		t.Errorf("expected shared stacktrace to be counted once but size grew by %d", got-base)
+	}
+
+	body := strings.Repeat("x", 1<<20)
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "string field", err: Wrap(err).SetField("body", body)},
+		{name: "byte slice field", err: Wrap(err).SetField("body", []byte(body))},
+		{name: "nested details", err: Wrap(err).SetDetails(map[string]interface{}{"req": &struct{ Body string }{body}})},
+		{name: "non-pkg wrapper", err: fmt.Errorf("%s: %w", body, err)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SizeOf(tt.err); got < base+len(body) {
+				t.Errorf("expected at least %d bytes but got %d", base+len(body), got)
+			}
+		})
+	}
+}
+
+func TestSetMaxErrorSize(t *testing.T) {
+	skipSmallFootprint(t)
+
+	SetMaxErrorSize(1 << 16)
+	defer SetMaxErrorSize(0)
+
+	var violations []Error
+	remove := AddHook(func(err Error) {
+		if ErrorCode(err) == CodeContractViolation {
+			violations = append(violations, err)
+		}
+	})
+	defer remove()
+
+	Foo().(Error).SetField("id", 42)
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations but got %v", violations)
+	}
+	Foo().(Error).SetField("body", strings.Repeat("x", 1<<16))
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation but got %v", violations)
+	}
+}
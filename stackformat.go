@@ -0,0 +1,96 @@
+package e
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// StackFormatter renders the frames of a stack as text for ErrorStacktrace.
+type StackFormatter interface {
+	FormatStack(frames []Frame) string
+}
+
+// StackFormatterFunc adapts a function to a StackFormatter.
+type StackFormatterFunc func(frames []Frame) string
+
+func (f StackFormatterFunc) FormatStack(frames []Frame) string {
+	return f(frames)
+}
+
+// Built-in StackFormatters.
+var (
+	// GoroutineStackFormatter renders frames like the goroutine traces of
+	// runtime/debug.Stack, without the goroutine header. Goroutine hand-offs
+	// are introduced by "received by goroutine:". It is the default.
+	//
+	//	github.com/acme/app.Foo(...)
+	//		/src/app/foo.go:12
+	GoroutineStackFormatter StackFormatter = StackFormatterFunc(formatGoroutineStack)
+
+	// OneLineStackFormatter renders frames on a single line, for log backends
+	// which split multi-line messages. Goroutine hand-offs are separated by
+	// "<<".
+	//
+	//	github.com/acme/app.Foo (/src/app/foo.go:12) < github.com/acme/app.main (/src/app/main.go:5)
+	OneLineStackFormatter StackFormatter = StackFormatterFunc(formatOneLineStack)
+
+	// JSONStackFormatter renders frames as a JSON array of Frame objects.
+	//
+	//	[{"function":"github.com/acme/app.Foo","file":"/src/app/foo.go","line":12}]
+	JSONStackFormatter StackFormatter = StackFormatterFunc(formatJSONStack)
+)
+
+// SetStackFormatter changes how ErrorStacktrace renders stacks, so that each
+// log backend gets the shape it needs. nil restores GoroutineStackFormatter.
+//
+// Usage:
+//
+//	e.SetStackFormatter(e.OneLineStackFormatter)
+func SetStackFormatter(formatter StackFormatter) {
+	Configure(func(c *Config) {
+		c.StackFormatter = formatter
+	})
+}
+
+func (c *Config) stackFormatter() StackFormatter {
+	if c.StackFormatter != nil {
+		return c.StackFormatter
+	}
+	return GoroutineStackFormatter
+}
+
+func formatGoroutineStack(frames []Frame) string {
+	sb := getBuffer()
+	defer putBuffer(sb)
+	for i, f := range frames {
+		if i > 0 && f.Handoff != frames[i-1].Handoff {
+			sb.WriteString("received by goroutine:\n")
+		}
+		fmt.Fprintf(sb, "%s(...)\n\t%s:%d\n", f.Function, f.File, f.Line)
+	}
+	return sb.String()
+}
+
+func formatOneLineStack(frames []Frame) string {
+	sb := getBuffer()
+	defer putBuffer(sb)
+	for i, f := range frames {
+		switch {
+		case i == 0:
+		case f.Handoff != frames[i-1].Handoff:
+			sb.WriteString(" << ")
+		default:
+			sb.WriteString(" < ")
+		}
+		fmt.Fprintf(sb, "%s (%s:%d)", f.Function, f.File, f.Line)
+	}
+	return sb.String()
+}
+
+func formatJSONStack(frames []Frame) string {
+	b, err := json.Marshal(frames)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
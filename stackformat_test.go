@@ -0,0 +1,54 @@
+package e
+
+import "testing"
+
+func TestStackFormatters(t *testing.T) {
+	frames := []Frame{
+		{Function: "github.com/acme/app.Foo", File: "/src/app/foo.go", Line: 12},
+		{Function: "github.com/acme/app.main", File: "/src/app/main.go", Line: 5},
+	}
+	tests := []struct {
+		name      string
+		formatter StackFormatter
+		want      string
+	}{
+		{
+			name:      "goroutine",
+			formatter: GoroutineStackFormatter,
+			want:      "github.com/acme/app.Foo(...)\n\t/src/app/foo.go:12\ngithub.com/acme/app.main(...)\n\t/src/app/main.go:5\n",
+		},
+		{
+			name:      "one line",
+			formatter: OneLineStackFormatter,
+			want:      "github.com/acme/app.Foo (/src/app/foo.go:12) < github.com/acme/app.main (/src/app/main.go:5)",
+		},
+		{
+			name:      "JSON",
+			formatter: JSONStackFormatter,
+			want:      `[{"function":"github.com/acme/app.Foo","file":"/src/app/foo.go","line":12},{"function":"github.com/acme/app.main","file":"/src/app/main.go","line":5}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter.FormatStack(frames); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetStackFormatter(t *testing.T) {
+	skipNoCapture(t)
+
+	SetStackFormatter(StackFormatterFunc(func(frames []Frame) string {
+		return frames[0].Function
+	}))
+	defer SetStackFormatter(nil)
+
+	if got := ErrorStacktrace(Wrap(Foo())); got != "github.com/kisunji/e.Foo" {
+		t.Errorf("expected custom formatter to be used but got %q", got)
+	}
+	if got := ErrorStacktrace(NewErrorNoTrace(CodeValidation, "invalid email")); got != "" {
+		t.Errorf("expected empty stacktrace without frames but got %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package e
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// StackTrim is a policy for cleaning stacks before ErrorStackFrames and
+// ErrorStacktrace return them. See SetStackTrim.
+type StackTrim struct {
+	// DropFunctions removes frames of functions with any of these prefixes,
+	// e.g. "runtime." or "testing.".
+	DropFunctions []string
+
+	// TrimPaths removes the first matching prefix from file names, e.g. the
+	// module root, so that frames show paths relative to it.
+	TrimPaths []string
+}
+
+// DefaultStackTrim returns a StackTrim which drops runtime and testing frames
+// and trims the working directory, GOROOT and the module cache from paths.
+func DefaultStackTrim() StackTrim {
+	trim := StackTrim{
+		DropFunctions: []string{"runtime.", "testing."},
+	}
+	if wd, err := os.Getwd(); err == nil {
+		trim.TrimPaths = append(trim.TrimPaths, filepath.ToSlash(wd)+"/")
+	}
+	if root := runtime.GOROOT(); root != "" {
+		trim.TrimPaths = append(trim.TrimPaths, filepath.ToSlash(root)+"/src/")
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			gopath = filepath.Join(home, "go")
+		}
+	}
+	for _, path := range filepath.SplitList(gopath) {
+		trim.TrimPaths = append(trim.TrimPaths, filepath.ToSlash(path)+"/pkg/mod/")
+	}
+	return trim
+}
+
+// SetStackTrim cleans every stack captured from now on with trim when it is
+// symbolized, so traces are not dominated by runtime and test harness frames.
+// nil disables cleaning.
+//
+// Usage:
+//
+//	trim := e.DefaultStackTrim()
+//	trim.DropFunctions = append(trim.DropFunctions, "github.com/acme/api/middleware.")
+//	e.SetStackTrim(&trim)
+func SetStackTrim(trim *StackTrim) {
+	Configure(func(c *Config) {
+		c.StackTrim = trim
+	})
+}
+
+// apply returns frames cleaned according to t. Frames are returned unchanged if
+// every frame would be dropped.
+func (t *StackTrim) apply(frames []Frame) []Frame {
+	if t == nil || len(frames) == 0 {
+		return frames
+	}
+
+	cleaned := make([]Frame, 0, len(frames))
+	for _, f := range frames {
+		if hasAnyPrefix(f.Function, t.DropFunctions) {
+			continue
+		}
+		for _, prefix := range t.TrimPaths {
+			if strings.HasPrefix(f.File, prefix) {
+				f.File = f.File[len(prefix):]
+				break
+			}
+		}
+		cleaned = append(cleaned, f)
+	}
+	if len(cleaned) == 0 {
+		return frames
+	}
+	return cleaned
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package e
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStackTrimApply(t *testing.T) {
+	trim := &StackTrim{
+		DropFunctions: []string{"runtime.", "testing."},
+		TrimPaths:     []string{"/src/app/", "/src/"},
+	}
+	tests := []struct {
+		name   string
+		trim   *StackTrim
+		frames []Frame
+		want   []Frame
+	}{
+		{
+			name: "drops functions and trims first matching path",
+			trim: trim,
+			frames: []Frame{
+				{Function: "github.com/acme/app.Foo", File: "/src/app/foo.go", Line: 1},
+				{Function: "testing.tRunner", File: "/go/src/testing/testing.go", Line: 2},
+				{Function: "github.com/acme/lib.Bar", File: "/src/lib/bar.go", Line: 3},
+				{Function: "runtime.goexit", File: "/go/src/runtime/asm_amd64.s", Line: 4},
+			},
+			want: []Frame{
+				{Function: "github.com/acme/app.Foo", File: "foo.go", Line: 1},
+				{Function: "github.com/acme/lib.Bar", File: "lib/bar.go", Line: 3},
+			},
+		},
+		{
+			name:   "keeps frames if all would be dropped",
+			trim:   trim,
+			frames: []Frame{{Function: "runtime.main", File: "/src/app/proc.go", Line: 1}},
+			want:   []Frame{{Function: "runtime.main", File: "/src/app/proc.go", Line: 1}},
+		},
+		{
+			name:   "nil trim keeps frames",
+			frames: []Frame{{Function: "runtime.main", File: "/src/app/proc.go", Line: 1}},
+			want:   []Frame{{Function: "runtime.main", File: "/src/app/proc.go", Line: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.trim.apply(tt.frames); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetStackTrim(t *testing.T) {
+	skipNoCapture(t)
+
+	trim := DefaultStackTrim()
+	SetStackTrim(&trim)
+	defer SetStackTrim(nil)
+
+	frames := ErrorStackFrames(Foo())
+	for _, f := range frames {
+		if strings.HasPrefix(f.Function, "runtime.") || strings.HasPrefix(f.Function, "testing.") {
+			t.Errorf("expected runtime and testing frames to be dropped but got %v", frames)
+		}
+	}
+	if len(frames) == 0 || frames[0].File != "error_test.go" {
+		t.Errorf("expected path relative to working directory but got %v", frames)
+	}
+}
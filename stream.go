@@ -0,0 +1,105 @@
+package e
+
+import (
+	"fmt"
+	"io"
+)
+
+// WrapAt wraps err like Wrap and records the position in a stream where it
+// occurred: the byte offset and the index of the element being processed,
+// e.g. a line or record. Both are added to the error text and to the
+// KeyOffset and KeyItem fields.
+//
+// Usage:
+//
+//	for i := 0; scanner.Scan(); i++ {
+//		if err := importRow(scanner.Bytes()); err != nil {
+//			return e.WrapAt(err, offset, i)
+//			// "Import: (at offset 5120, item 41): importRow: ..."
+//		}
+//		offset += int64(len(scanner.Bytes())) + 1
+//	}
+func WrapAt(err error, offset int64, item int) Error {
+	if err == nil {
+		return nil
+	}
+
+	wrapped := wrap(fmt.Errorf("(at offset %d, item %d): %w", offset, item, err), 3) // localizer.Ignore
+	return wrapped.SetFields(map[string]interface{}{KeyOffset: offset, KeyItem: item})
+}
+
+// Stream tracks the position of an io.Reader or io.Writer pipeline so that
+// errors can be wrapped with it by Wrap. Bytes are counted as they are read
+// or written and items are counted by calling Next.
+//
+// A Stream is not safe for concurrent use.
+type Stream struct {
+	r      io.Reader
+	w      io.Writer
+	offset int64
+	item   int
+}
+
+// NewStreamReader returns a Stream which reads from r.
+//
+// Usage:
+//
+//	s := e.NewStreamReader(f)
+//	dec := json.NewDecoder(s)
+//	for ; dec.More(); s.Next() {
+//		if err := dec.Decode(&rec); err != nil {
+//			return s.Wrap(err)
+//		}
+//	}
+func NewStreamReader(r io.Reader) *Stream {
+	return &Stream{r: r}
+}
+
+// NewStreamWriter returns a Stream which writes to w.
+func NewStreamWriter(w io.Writer) *Stream {
+	return &Stream{w: w}
+}
+
+// Read reads from the underlying reader and advances the offset. It panics if
+// s was not created by NewStreamReader.
+func (s *Stream) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.offset += int64(n)
+	return n, err
+}
+
+// Write writes to the underlying writer and advances the offset. It panics if
+// s was not created by NewStreamWriter.
+func (s *Stream) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	s.offset += int64(n)
+	return n, err
+}
+
+// Next advances the item index, starting from 0, and returns the new index.
+func (s *Stream) Next() int {
+	s.item++
+	return s.item
+}
+
+// Offset returns the number of bytes read or written so far.
+func (s *Stream) Offset() int64 {
+	return s.offset
+}
+
+// Item returns the index of the current item.
+func (s *Stream) Item() int {
+	return s.item
+}
+
+// Wrap wraps err like WrapAt with the current position of s. Note that readers
+// such as bufio.Reader and json.Decoder read ahead, so the offset is where the
+// underlying reader stopped rather than where the failing item starts.
+func (s *Stream) Wrap(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	wrapped := wrap(fmt.Errorf("(at offset %d, item %d): %w", s.offset, s.item, err), 3) // localizer.Ignore
+	return wrapped.SetFields(map[string]interface{}{KeyOffset: s.offset, KeyItem: s.item})
+}
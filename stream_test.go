@@ -0,0 +1,66 @@
+package e
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapAt(t *testing.T) {
+	skipNoCapture(t)
+
+	if WrapAt(nil, 1, 2) != nil {
+		t.Errorf("expected nil")
+	}
+
+	err := WrapAt(errors.New("bad row"), 5120, 41)
+	if got, want := err.Error(), "TestWrapAt: (at offset 5120, item 41): bad row"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+	want := map[string]interface{}{KeyOffset: int64(5120), KeyItem: 41}
+	if got := ErrorFields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestStream(t *testing.T) {
+	skipNoCapture(t)
+
+	t.Run("reader tracks position", func(t *testing.T) {
+		s := NewStreamReader(strings.NewReader("ok\nok\nbad\n"))
+		scanner := bufio.NewScanner(s)
+		scanner.Buffer(make([]byte, 4), 4)
+		var err error
+		for ; scanner.Scan(); s.Next() {
+			if scanner.Text() == "bad" {
+				err = s.Wrap(errors.New("bad line"))
+				break
+			}
+		}
+		want := map[string]interface{}{KeyOffset: s.Offset(), KeyItem: 2}
+		if got := ErrorFields(err); !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %v\nwant: %v", got, want)
+		}
+		if s.Offset() < 7 {
+			t.Errorf("expected offset past the second line but got %d", s.Offset())
+		}
+	})
+	t.Run("writer tracks position", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := NewStreamWriter(&buf)
+		s.Write([]byte("header\n"))
+		s.Next()
+		err := s.Wrap(errors.New("cannot encode"))
+		if got, want := err.Error(), "TestStream.func2: (at offset 7, item 1): cannot encode"; got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("nil error returns nil", func(t *testing.T) {
+		if NewStreamWriter(&bytes.Buffer{}).Wrap(nil) != nil {
+			t.Errorf("expected nil")
+		}
+	})
+}
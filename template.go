@@ -0,0 +1,159 @@
+package e
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Placeholders recognised by SetErrorTemplate.
+const (
+	templateOp    = "{op}"
+	templateOps   = "{ops}"
+	templateCode  = "{code}"
+	templateCause = "{cause}"
+)
+
+// DefaultErrorTemplate is the layout used by Error() unless changed with
+// SetErrorTemplate, e.g. "Foo: [database_error] cannot foo".
+const DefaultErrorTemplate = templateOp + ": [" + templateCode + "] " + templateCause
+
+// errorTemplate is a parsed template made of literal text and placeholders.
+type errorTemplate []templateSegment
+
+// templateSegment is either literal text which is always rendered, or a
+// placeholder with the punctuation around it, which is rendered only if the
+// placeholder is not empty.
+type templateSegment struct {
+	literal     string
+	placeholder string
+	prefix      string
+	suffix      string
+}
+
+// SetErrorTemplate changes the order of the components rendered by Error() for
+// every Error in a chain. The template may contain the placeholders:
+//
+//	{op}    the op, also accepted as {ops} since it is rendered for each
+//	        Error of the chain
+//	{code}  the code
+//	{cause} the nested error
+//
+// Placeholders render their component only, so the template controls the
+// punctuation. Text touching a placeholder, together with the spaces which
+// follow it, is omitted along with an empty component. {cause} is required.
+// For example "[{code}] {ops}: {cause}" renders
+// "[database_error] Foo: cannot foo", or "Foo: cannot foo" without a code.
+func SetErrorTemplate(template string) error {
+	return updateConfig(func(c *Config) error {
+		c.ErrorTemplate = template
+		return nil
+	})
+}
+
+func parseErrorTemplate(template string) (errorTemplate, error) {
+	var (
+		raw      []templateSegment
+		hasCause bool
+	)
+	for len(template) > 0 {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			raw = append(raw, templateSegment{literal: template})
+			break
+		}
+		if start > 0 {
+			raw = append(raw, templateSegment{literal: template[:start]})
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder in error template: %q", template[start:])
+		}
+		placeholder := template[start : start+end+1]
+		switch placeholder {
+		case templateOp, templateOps:
+			placeholder = templateOp
+		case templateCode:
+		case templateCause:
+			hasCause = true
+		default:
+			return nil, fmt.Errorf("unknown placeholder in error template: %q", placeholder)
+		}
+		raw = append(raw, templateSegment{placeholder: placeholder})
+		template = template[start+end+1:]
+	}
+	if !hasCause {
+		return nil, fmt.Errorf("error template must contain %s", templateCause)
+	}
+	return attachPunctuation(raw), nil
+}
+
+// attachPunctuation moves the text touching each placeholder of raw into its
+// prefix and suffix. The suffix also takes the spaces following it.
+func attachPunctuation(raw []templateSegment) errorTemplate {
+	parsed := make(errorTemplate, 0, len(raw))
+	for i := range raw {
+		seg := raw[i]
+		if seg.placeholder != "" {
+			parsed = append(parsed, seg)
+			continue
+		}
+
+		text := seg.literal
+		if i > 0 {
+			n := 0
+			for n < len(text) && !isTemplateSpace(text[n]) {
+				n++
+			}
+			for n < len(text) && isTemplateSpace(text[n]) {
+				n++
+			}
+			parsed[len(parsed)-1].suffix = text[:n]
+			text = text[n:]
+		}
+		var prefix string
+		if i+1 < len(raw) {
+			n := len(text)
+			for n > 0 && !isTemplateSpace(text[n-1]) {
+				n--
+			}
+			text, prefix = text[:n], text[n:]
+		}
+		if text != "" {
+			parsed = append(parsed, templateSegment{literal: text})
+		}
+		if prefix != "" {
+			raw[i+1].prefix = prefix
+		}
+	}
+	return parsed
+}
+
+func isTemplateSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func (t errorTemplate) render(e errorImpl) string {
+	sb := getBuffer()
+	defer putBuffer(sb)
+	for _, seg := range t {
+		var value string
+		switch seg.placeholder {
+		case "":
+			sb.WriteString(seg.literal)
+			continue
+		case templateOp:
+			value = e.op
+		case templateCode:
+			value = string(e.code)
+		case templateCause:
+			value = causeString(e.err)
+		}
+		if value == "" {
+			continue
+		}
+		sb.WriteString(seg.prefix)
+		sb.WriteString(value)
+		sb.WriteString(seg.suffix)
+	}
+	return sb.String()
+}
@@ -0,0 +1,77 @@
+package e
+
+import "testing"
+
+func TestSetErrorTemplate(t *testing.T) {
+	skipNoCapture(t)
+
+	defer SetErrorTemplate(DefaultErrorTemplate)
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "default template",
+			template: DefaultErrorTemplate,
+			want:     "Bar: Foo: [database_error] cannot foo",
+		},
+		{
+			name:     "default template parsed",
+			template: "{ops}: [{code}] {cause}",
+			want:     "Bar: Foo: [database_error] cannot foo",
+		},
+		{
+			name:     "spaces between placeholders",
+			template: "{code} {ops} {cause}",
+			want:     "Bar database_error Foo cannot foo",
+		},
+		{
+			name:     "code first",
+			template: "[{code}] {op}: {cause}",
+			want:     "Bar: [database_error] Foo: cannot foo",
+		},
+		{
+			name:     "punctuation around cause",
+			template: "{op}: [{code}] <{cause}>",
+			want:     "Bar: <Foo: [database_error] <cannot foo>>",
+		},
+		{
+			name:     "literal text",
+			template: "error: {op}: {cause}",
+			want:     "error: Bar: error: Foo: cannot foo",
+		},
+		{
+			name:     "missing cause",
+			template: "{op}{code}",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown placeholder",
+			template: "{opz}{cause}",
+			wantErr:  true,
+		},
+		{
+			name:     "unterminated placeholder",
+			template: "{cause}{op",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetErrorTemplate(DefaultErrorTemplate)
+			err := SetErrorTemplate(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetErrorTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := Bar().Error(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package e
+
+// TemplateFuncs returns functions for html/template and text/template which
+// expose the client-facing parts of an error, so server-rendered error pages can
+// show them without preprocessing in handlers and without leaking internals
+// such as Error() or stacktraces:
+//
+//	errorCode        ErrorCode
+//	errorMessage     ErrorMessage
+//	errorMessageOr   ErrorMessageOr
+//	errorSuggestion  ErrorSuggestion
+//	errorHelpURL     ErrorHelpURL
+//	errorID          ErrorID
+//
+// The result can be passed directly to Funcs since it is assignable to
+// template.FuncMap.
+//
+// Usage:
+//
+//	var errorPage = template.Must(template.New("error").Funcs(e.TemplateFuncs()).Parse(`
+//		<h1>{{errorMessageOr .Err "Something went wrong"}}</h1>
+//		{{with errorSuggestion .Err}}<p>{{.}}</p>{{end}}
+//		<small>Reference: {{errorID .Err}}</small>
+//	`))
+func TemplateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"errorCode":       ErrorCode,
+		"errorMessage":    ErrorMessage,
+		"errorMessageOr":  ErrorMessageOr,
+		"errorSuggestion": ErrorSuggestion,
+		"errorHelpURL":    ErrorHelpURL,
+		"errorID":         ErrorID,
+	}
+}
@@ -0,0 +1,42 @@
+package e
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	page := template.Must(template.New("error").Funcs(TemplateFuncs()).Parse(
+		`{{errorCode .}}|{{errorMessageOr . "Something went wrong"}}|{{errorSuggestion .}}|{{errorHelpURL .}}|{{errorID .}}`))
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "renders client-facing parts",
+			err: Wrap(Foo()).SetMessage("Cannot save <draft>").SetSuggestion("Try again").
+				SetHelpURL("https://example.com/help").SetID("abc"),
+			want: "database_error|Cannot save &lt;draft&gt;|Try again|https://example.com/help|abc",
+		},
+		{
+			name: "non-pkg error uses fallback",
+			err:  errors.New("internal detail"),
+			want: "|Something went wrong|||",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := page.Execute(&sb, tt.err); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
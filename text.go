@@ -0,0 +1,24 @@
+package e
+
+import "sync/atomic"
+
+// errorText caches the text of an Error, which log pipelines often request
+// several times per error. It is allocated when an Error is constructed, since
+// Error() has a value receiver and cannot store it later.
+type errorText struct {
+	rendered atomic.Value // renderedText
+}
+
+// renderedText is the text of an Error rendered with the template of config.
+// Changing the Config, e.g. with SetErrorTemplate, renders the text again.
+type renderedText struct {
+	config *Config
+	text   string
+}
+
+// withText returns e with an empty cache for the text of Error(), for use
+// after changing its op, code or err.
+func (e errorImpl) withText() errorImpl {
+	e.text = new(errorText)
+	return e
+}
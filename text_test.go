@@ -0,0 +1,80 @@
+package e
+
+import (
+	"context"
+	"testing"
+)
+
+func TestErrorTextCached(t *testing.T) {
+	skipNoCapture(t)
+
+	if loadConfig() != loadConfig() {
+		t.Fatalf("expected the active Config to be stable between changes")
+	}
+
+	err := Wrap(Foo())
+	if got := err.Error(); got != "TestErrorTextCached: Foo: [database_error] cannot foo" {
+		t.Fatalf("unexpected text %q", got)
+	}
+
+	withMessage := err.SetMessage("Try again later.")
+	if withMessage.(errorImpl).text != err.(errorImpl).text {
+		t.Errorf("expected SetMessage to share the cached text")
+	}
+
+	withCode := err.SetCode(CodeUnexpected)
+	if got := withCode.Error(); got != "TestErrorTextCached: [unexpected_error] Foo: [database_error] cannot foo" {
+		t.Errorf("expected SetCode to render again but got %q", got)
+	}
+	if got := err.Error(); got != "TestErrorTextCached: Foo: [database_error] cannot foo" {
+		t.Errorf("expected original text to be kept but got %q", got)
+	}
+
+	if setErr := SetErrorTemplate("[{code}] {op}: {cause}"); setErr != nil {
+		t.Fatal(setErr)
+	}
+	defer SetErrorTemplate(DefaultErrorTemplate)
+	if got := err.Error(); got != "TestErrorTextCached: [database_error] Foo: cannot foo" {
+		t.Errorf("expected a new template to render again but got %q", got)
+	}
+}
+
+func TestErrorTextCachedAcrossDowngrade(t *testing.T) {
+	skipNoCapture(t)
+	skipSmallFootprint(t)
+
+	SetCancellationPolicy(CancellationDowngrade)
+	defer SetCancellationPolicy(CancellationDrop)
+
+	var hooked string
+	remove := AddHook(func(err Error) { hooked = err.Error() })
+	defer remove()
+
+	err := Wrap(context.Canceled)
+	if hooked != "TestErrorTextCachedAcrossDowngrade: [canceled] context canceled" {
+		t.Errorf("unexpected text seen by hook %q", hooked)
+	}
+	if got := err.Error(); got != "TestErrorTextCachedAcrossDowngrade: context canceled" {
+		t.Errorf("expected downgrade to be hidden from caller but got %q", got)
+	}
+}
+
+func BenchmarkError(b *testing.B) {
+	err := Wrap(Bar())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = err.Error()
+	}
+}
+
+// BenchmarkErrorRender measures Error() without the text cache.
+func BenchmarkErrorRender(b *testing.B) {
+	err := Wrap(Bar()).(errorImpl)
+	c := loadConfig()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = err.render(c)
+	}
+}
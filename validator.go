@@ -0,0 +1,83 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// FieldError is implemented by the per-field errors of validation libraries,
+// such as validator.FieldError of github.com/go-playground/validator, so that
+// FromValidator can convert them without depending on those libraries.
+type FieldError interface {
+	// Field returns the name of the invalid field.
+	Field() string
+
+	// Tag returns the validation rule which failed, e.g. "required".
+	Tag() string
+}
+
+// FieldViolation is the client-facing detail recorded by FromValidator for
+// each invalid field.
+type FieldViolation struct {
+	Field string `json:"field"`
+	Tag   string `json:"tag"`
+}
+
+// FromValidator converts validation errors into an Error with code
+// CodeValidation, a user-friendly message and a []FieldViolation as details.
+// err may be a FieldError, or a slice of FieldError such as
+// validator.ValidationErrors, anywhere in its chain. Other errors are only
+// wrapped since they are not caused by the input.
+//
+// Usage:
+//
+//	if err := validate.Struct(req); err != nil {
+//		return e.FromValidator(err)
+//	}
+func FromValidator(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	converted := wrap(err, 3)
+	violations := fieldViolations(err)
+	if len(violations) == 0 {
+		return converted
+	}
+	converted.code = CodeValidation
+	if len(violations) == 1 {
+		converted.message = fmt.Sprintf("Field %q failed %q validation.", violations[0].Field, violations[0].Tag)
+	} else {
+		converted.message = fmt.Sprintf("%d fields failed validation.", len(violations))
+	}
+	converted.details = &detailsBox{violations}
+	return converted.withText()
+}
+
+// fieldViolations returns the violations of the first error in the chain of err
+// which is a FieldError or a non-empty slice of FieldError.
+func fieldViolations(err error) []FieldViolation {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if f, ok := err.(FieldError); ok {
+			return []FieldViolation{{Field: f.Field(), Tag: f.Tag()}}
+		}
+		v := reflect.ValueOf(err)
+		if v.Kind() != reflect.Slice || v.Len() == 0 {
+			continue
+		}
+		violations := make([]FieldViolation, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			f, ok := v.Index(i).Interface().(FieldError)
+			if !ok {
+				violations = nil
+				break
+			}
+			violations = append(violations, FieldViolation{Field: f.Field(), Tag: f.Tag()})
+		}
+		if violations != nil {
+			return violations
+		}
+	}
+	return nil
+}
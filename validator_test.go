@@ -0,0 +1,98 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeFieldError and fakeValidationErrors mirror validator.FieldError and
+// validator.ValidationErrors of github.com/go-playground/validator.
+type fakeFieldError struct {
+	field, tag string
+}
+
+func (f fakeFieldError) Field() string { return f.field }
+func (f fakeFieldError) Tag() string   { return f.tag }
+func (f fakeFieldError) Error() string {
+	return fmt.Sprintf("Key: %q Error:Field validation for %q failed on the %q tag", f.field, f.field, f.tag)
+}
+
+type fakeFieldErrorIface interface {
+	error
+	Field() string
+	Tag() string
+}
+
+type fakeValidationErrors []fakeFieldErrorIface
+
+func (ve fakeValidationErrors) Error() string {
+	msgs := make([]string, 0, len(ve))
+	for _, f := range ve {
+		msgs = append(msgs, f.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func TestFromValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    Code
+		wantMessage string
+		wantDetails interface{}
+	}{
+		{
+			name:        "single field",
+			err:         fakeValidationErrors{fakeFieldError{"Email", "required"}},
+			wantCode:    CodeValidation,
+			wantMessage: `Field "Email" failed "required" validation.`,
+			wantDetails: []FieldViolation{{Field: "Email", Tag: "required"}},
+		},
+		{
+			name:        "several fields",
+			err:         fakeValidationErrors{fakeFieldError{"Email", "email"}, fakeFieldError{"Age", "gte"}},
+			wantCode:    CodeValidation,
+			wantMessage: "2 fields failed validation.",
+			wantDetails: []FieldViolation{{Field: "Email", Tag: "email"}, {Field: "Age", Tag: "gte"}},
+		},
+		{
+			name:        "wrapped field error",
+			err:         fmt.Errorf("decode: %w", fakeFieldError{"Name", "max"}),
+			wantCode:    CodeValidation,
+			wantMessage: `Field "Name" failed "max" validation.`,
+			wantDetails: []FieldViolation{{Field: "Name", Tag: "max"}},
+		},
+		{
+			name: "other errors are only wrapped",
+			err:  errors.New("invalid validation target"),
+		},
+		{
+			name: "empty validation errors are only wrapped",
+			err:  fakeValidationErrors{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromValidator(tt.err)
+			if got := ErrorCode(err); got != tt.wantCode {
+				t.Errorf("code\ngot:  %q\nwant: %q", got, tt.wantCode)
+			}
+			if got := ErrorMessage(err); got != tt.wantMessage {
+				t.Errorf("message\ngot:  %q\nwant: %q", got, tt.wantMessage)
+			}
+			if got := ErrorDetails(err); !reflect.DeepEqual(got, tt.wantDetails) {
+				t.Errorf("details\ngot:  %#v\nwant: %#v", got, tt.wantDetails)
+			}
+			if !reflect.DeepEqual(errors.Unwrap(err), tt.err) {
+				t.Errorf("expected converted error to wrap original")
+			}
+		})
+	}
+
+	if FromValidator(nil) != nil {
+		t.Errorf("expected nil")
+	}
+}
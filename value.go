@@ -0,0 +1,78 @@
+package e
+
+// Key identifies a value attached to an Error with WithValue. Keys are compared
+// by identity, so values attached by different packages never collide even if
+// their names do.
+type Key struct {
+	name *string
+}
+
+// NewKey returns a new Key. name is only used for debugging.
+func NewKey(name string) Key {
+	return Key{name: &name}
+}
+
+func (k Key) String() string {
+	if k.name == nil {
+		return "<nil>"
+	}
+	return *k.name
+}
+
+// valueMap is never modified once attached to an Error; WithValue copies it.
+type valueMap map[Key]interface{}
+
+// WithValue attaches a strongly-typed value to err under key, so it can be
+// retrieved at the boundary with Value without type assertions. err is wrapped
+// first if it was not created by this package. Returns nil if err is nil.
+//
+// Usage:
+//
+//	var requestInfoKey = e.NewKey("request_info")
+//
+//	err = e.WithValue(e.Wrap(err), requestInfoKey, RequestInfo{Path: r.URL.Path})
+//
+//	...
+//
+//	if info, ok := e.Value[RequestInfo](err, requestInfoKey); ok {
+//		log.Printf("failed request to %s", info.Path)
+//	}
+func WithValue[T any](err Error, key Key, val T) Error {
+	if err == nil {
+		return nil
+	}
+
+	e, ok := err.(errorImpl)
+	if !ok {
+		e = wrap(err, 3)
+	}
+
+	values := make(valueMap, 1)
+	if e.values != nil {
+		for k, v := range *e.values {
+			values[k] = v
+		}
+	}
+	values[key] = val
+	e.values = &values
+	checkSize(e)
+	return e
+}
+
+// Value returns the outermost value attached to the chain of err under key with
+// WithValue. ok is false if there is no such value or it is not of type T.
+func Value[T any](err error, key Key) (val T, ok bool) {
+	Walk(err, func(err error) bool {
+		e, isImpl := err.(errorImpl)
+		if !isImpl || e.values == nil {
+			return true
+		}
+		v, found := (*e.values)[key]
+		if !found {
+			return true
+		}
+		val, ok = v.(T)
+		return false
+	})
+	return val, ok
+}
@@ -0,0 +1,44 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type requestInfo struct {
+	Path string
+}
+
+var (
+	requestInfoKey = NewKey("request_info")
+	attemptsKey    = NewKey("attempts")
+)
+
+func TestValue(t *testing.T) {
+	inner := WithValue(NewError(CodeInternal, "cannot foo"), attemptsKey, 1)
+	err := WithValue(Wrap(fmt.Errorf("fmt: %w", inner)), requestInfoKey, requestInfo{Path: "/bar"})
+	err = WithValue(err, attemptsKey, 3)
+
+	if info, ok := Value[requestInfo](err, requestInfoKey); !ok || info.Path != "/bar" {
+		t.Errorf("expected request info but got %+v, %v", info, ok)
+	}
+	if attempts, ok := Value[int](err, attemptsKey); !ok || attempts != 3 {
+		t.Errorf("expected outermost value 3 but got %v, %v", attempts, ok)
+	}
+	if attempts, ok := Value[int](inner, attemptsKey); !ok || attempts != 1 {
+		t.Errorf("expected inner value to be kept but got %v, %v", attempts, ok)
+	}
+	if _, ok := Value[string](err, attemptsKey); ok {
+		t.Errorf("expected value of wrong type not to be found")
+	}
+	if _, ok := Value[int](err, NewKey("attempts")); ok {
+		t.Errorf("expected keys with the same name not to collide")
+	}
+	if WithValue(nil, attemptsKey, 1) != nil {
+		t.Errorf("expected nil")
+	}
+	if !errors.Is(err, inner) {
+		t.Errorf("expected errors with values to be comparable")
+	}
+}
@@ -0,0 +1,127 @@
+package e
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Walk calls fn for err and every error it wraps, outermost first. Errors which
+// wrap several errors (Unwrap() []error, as produced by errors.Join) have each
+// child walked in order. Walk stops as soon as fn returns false.
+func Walk(err error, fn func(err error) bool) {
+	walk(err, fn)
+}
+
+func walk(err error, fn func(err error) bool) bool {
+	for err != nil {
+		if !fn(err) {
+			return false
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, child := range u.Unwrap() {
+				if !walk(child, fn) {
+					return false
+				}
+			}
+			return true
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return true
+		}
+	}
+	return true
+}
+
+// ErrorCodes returns every non-empty Code found by Walk, outermost first. Unlike
+// ErrorCode it also finds the codes of each child of a joined error.
+//
+// If a namespace was set with SetCodeNamespace, each code is returned as
+// "namespace.code".
+func ErrorCodes(err error) []Code {
+	ns := getCodeNamespace()
+	var codes []Code
+	Walk(err, func(err error) bool {
+		if e, ok := err.(ClientFacing); ok && e.ClientCode() != "" {
+			if ns != "" {
+				codes = append(codes, Code(ns+"."+e.ClientCode()))
+			} else {
+				codes = append(codes, Code(e.ClientCode()))
+			}
+		}
+		return true
+	})
+	return codes
+}
+
+// AllCodes is an alias of ErrorCodes, which already returns every code
+// encountered while unwrapping err, outermost first, including codes overridden
+// by an outer SetCode.
+//
+// Usage:
+//
+//	err := e.Wrap(repoErr).SetCode("unavailable") // repoErr has "database_error"
+//	e.AllCodes(err) // ["unavailable", "database_error"]
+func AllCodes(err error) []Code {
+	return ErrorCodes(err)
+}
+
+// Leaves returns every leaf error of err, i.e. errors found by Walk which do not
+// wrap another error. For a joined error this is the root cause of each child,
+// so handlers can inspect every root cause of a batch failure.
+func Leaves(err error) []error {
+	var leaves []error
+	Walk(err, func(err error) bool {
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			if len(u.Unwrap()) > 0 {
+				return true
+			}
+		case interface{ Unwrap() error }:
+			if u.Unwrap() != nil {
+				return true
+			}
+		}
+		leaves = append(leaves, err)
+		return true
+	})
+	return leaves
+}
+
+// CauseTypes returns the concrete Go type of every error found by Walk, such as
+// "*net.OpError" or "*pq.Error". Types which carry no information are skipped:
+// errors created by this package, errors.New and fmt.Errorf. This allows
+// querying how often a root cause occurs without parsing messages.
+func CauseTypes(err error) []string {
+	var types []string
+	Walk(err, func(err error) bool {
+		if _, ok := err.(errorImpl); ok {
+			return true
+		}
+		switch t := fmt.Sprintf("%T", err); t {
+		case "*errors.errorString", "*fmt.wrapError", "*fmt.wrapErrors":
+		default:
+			types = append(types, t)
+		}
+		return true
+	})
+	return types
+}
+
+// causeString renders the nested error of an Error. Joined errors are rendered
+// on one line with the index of each child, e.g. "[0] cannot foo; [1] cannot bar".
+func causeString(err error) string {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return err.Error()
+	}
+	var sb strings.Builder
+	for i, child := range joined.Unwrap() {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(fmt.Sprintf("[%d] %v", i, child))
+	}
+	return sb.String()
+}
@@ -0,0 +1,90 @@
+package e
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// joinError mimics errors.Join, which is not available in all supported Go
+// versions.
+type joinError []error
+
+func (j joinError) Error() string {
+	return fmt.Sprint([]error(j))
+}
+
+func (j joinError) Unwrap() []error {
+	return j
+}
+
+func TestWalk(t *testing.T) {
+	skipNoCapture(t)
+
+	inner := NewError(CodeInternal, "cannot bar")
+	joined := joinError{Foo(), fmt.Errorf("fmt: %w", inner), errors.New("basic error")}
+	err := Wrap(joined).SetCode(CodeUnexpected)
+
+	t.Run("visits joined children", func(t *testing.T) {
+		var count int
+		Walk(err, func(error) bool {
+			count++
+			return true
+		})
+		// err, joined, Foo and its cause, fmt wrap, inner and its cause, basic error
+		if count != 8 {
+			t.Errorf("expected 8 errors but walked %d", count)
+		}
+	})
+	t.Run("stops when fn returns false", func(t *testing.T) {
+		var count int
+		Walk(err, func(error) bool {
+			count++
+			return count < 3
+		})
+		if count != 3 {
+			t.Errorf("expected to stop after 3 errors but walked %d", count)
+		}
+	})
+	t.Run("ErrorCodes returns codes of every child", func(t *testing.T) {
+		want := []Code{CodeUnexpected, CodeDatabase, CodeInternal}
+		if got := ErrorCodes(err); !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("AllCodes includes overridden codes", func(t *testing.T) {
+		want := []Code{CodeInternal, CodeDatabase}
+		if got := AllCodes(Wrap(Foo()).SetCode(CodeInternal)); !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("Leaves returns root cause of every child", func(t *testing.T) {
+		leaves := Leaves(err)
+		if len(leaves) != 3 {
+			t.Fatalf("expected 3 leaves but got %v", leaves)
+		}
+		want := []string{"cannot foo", "cannot bar", "basic error"}
+		for i, leaf := range leaves {
+			if leaf.Error() != want[i] {
+				t.Errorf("leaf %d\ngot:  %q\nwant: %q", i, leaf, want[i])
+			}
+		}
+		if got := Leaves(nil); got != nil {
+			t.Errorf("expected no leaves but got %v", got)
+		}
+	})
+	t.Run("CauseTypes skips types without information", func(t *testing.T) {
+		want := []string{"e.joinError"}
+		if got := CauseTypes(err); !reflect.DeepEqual(got, want) {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+	t.Run("renders joined children with indices", func(t *testing.T) {
+		want := "TestWalk: [unexpected_error] [0] Foo: [database_error] cannot foo; " +
+			"[1] fmt: TestWalk: [internal_error] cannot bar; [2] basic error"
+		if got := err.Error(); got != want {
+			t.Errorf("\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+}